@@ -0,0 +1,227 @@
+package amqp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/amqp"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+// defaultRecoveryInterval is used when no WithRecoveryInterval option is given.
+const defaultRecoveryInterval = time.Second
+
+// RabbitMQ is provided as a queueConnector to ezQueue.Connect method, to connect to an AMQP 0-9-1 queue.
+func RabbitMQ(options OptionFunc) (api.Enqueuer[amqp.Message], api.Dequeuer[amqp.Message], error) {
+	return connect(options)
+}
+
+// connect establishes both enqueue and dequeue connections.
+func connect(opts OptionFunc) (api.Enqueuer[amqp.Message], api.Dequeuer[amqp.Message], error) {
+
+	// Initialise Enqueuer
+	enq, err := connectEnqueue(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialise Dequeuer
+	deq, err := connectDequeue(opts)
+	if err != nil {
+		_ = enq.Disconnect(context.Background())
+		return nil, nil, err
+	}
+
+	return enq, deq, nil
+}
+
+// connectEnqueue establishes an enqueue connection.
+func connectEnqueue(options OptionFunc) (api.Enqueuer[amqp.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("amqp: options is nil")
+	}
+	opts := options()
+
+	// Validate queueName
+	if opts.queueName == "" {
+		return nil, fmt.Errorf("amqp: queueName is empty")
+	}
+
+	connOpts := buildConnOptions(opts)
+
+	// Publish straight to the queue unless an exchange was given
+	routingKey := connOpts.routingKey
+	if connOpts.exchange == "" && routingKey == "" {
+		routingKey = opts.queueName
+	}
+
+	// connect to the broker
+	enq := amqp.NewEnqueuer(dialer(connOpts), connOpts.exchange, routingKey, connOpts.recoveryInterval)
+	err := connectOnce(enq.Connect)
+	if err != nil {
+		return nil, err
+	}
+
+	return enq, nil
+}
+
+// connectDequeue establishes a dequeue connection.
+func connectDequeue(options OptionFunc) (api.Dequeuer[amqp.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("amqp: options is nil")
+	}
+	opts := options()
+
+	// Validate queueName
+	if opts.queueName == "" {
+		return nil, fmt.Errorf("amqp: queueName is empty")
+	}
+
+	connOpts := buildConnOptions(opts)
+
+	// connect to the broker
+	deq := amqp.NewDequeuer(dialer(connOpts), opts.queueName, connOpts.prefetch, connOpts.recoveryInterval)
+	err := connectOnce(deq.Connect)
+	if err != nil {
+		return nil, err
+	}
+
+	return deq, nil
+}
+
+// connectOnce makes a single connection attempt, so that a broker that is
+// unreachable at start-up is reported instead of retried indefinitely.
+func connectOnce(connect func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return connect(ctx)
+}
+
+// buildConnOptions applies the ConnOptionFuncs over the defaults.
+func buildConnOptions(opts Options) *connOptions {
+	connOpts := &connOptions{
+		Server:           "localhost",
+		Port:             5672,
+		Username:         "guest",
+		Password:         "guest",
+		VHost:            "/",
+		recoveryInterval: defaultRecoveryInterval,
+	}
+	for _, opt := range opts.connOpts {
+		opt(connOpts)
+	}
+	return connOpts
+}
+
+// dialer builds the AMQP URL from connOpts and returns a Dialer for it.
+func dialer(connOpts *connOptions) amqp.Dialer {
+	uri := amqp091.URI{
+		Scheme:   "amqp",
+		Host:     connOpts.Server,
+		Port:     int(connOpts.Port),
+		Username: connOpts.Username,
+		Password: connOpts.Password,
+		Vhost:    connOpts.VHost,
+	}
+	if connOpts.tls != nil {
+		uri.Scheme = "amqps"
+	}
+	return amqp.Dial(uri.String(), amqp091.Config{TLSClientConfig: connOpts.tls})
+}
+
+// Options struct holds connection options and queue name.
+type Options struct {
+	connOpts  []ConnOptionFunc
+	queueName string
+}
+
+// OptionFunc is a function type that returns Options.
+type OptionFunc func() Options
+
+// Queue returns an OptionFunc that holds connection options and a queue name.
+func Queue(queue string, connOpts ...ConnOptionFunc) OptionFunc {
+	return func() Options {
+		return Options{
+			connOpts:  connOpts,
+			queueName: queue,
+		}
+	}
+}
+
+// ConnOptionFunc is a function type to set connOptions.
+type ConnOptionFunc func(*connOptions)
+
+// connOptions struct holds the information required for creating connections.
+type connOptions struct {
+	Username         string
+	Password         string
+	Server           string
+	Port             uint16
+	VHost            string
+	tls              *tls.Config
+	exchange         string
+	routingKey       string
+	prefetch         int
+	recoveryInterval time.Duration
+}
+
+// AuthenticatedWith sets username and password for ConnOptionFunc.
+func AuthenticatedWith(username string, password string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.Username = username
+		opts.Password = password
+	}
+}
+
+// LocatedAt sets server and port for ConnOptionFunc.
+func LocatedAt(server string, port uint16) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.Port = port
+		opts.Server = server
+	}
+}
+
+// UsingVHost sets the virtual host for ConnOptionFunc.
+func UsingVHost(vhost string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.VHost = vhost
+	}
+}
+
+// UsingTLS connects over amqps with the given TLS configuration.
+func UsingTLS(config *tls.Config) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.tls = config
+	}
+}
+
+// PublishingTo publishes enqueued messages to the given exchange and routing key,
+// instead of directly to the queue through the default exchange.
+func PublishingTo(exchange string, routingKey string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.exchange = exchange
+		opts.routingKey = routingKey
+	}
+}
+
+// WithPrefetch limits the number of unacknowledged messages the broker delivers
+// to the Dequeuer at a time.
+func WithPrefetch(count int) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.prefetch = count
+	}
+}
+
+// WithRecoveryInterval sets the time waited between attempts to re-open a lost
+// connection or channel.
+func WithRecoveryInterval(interval time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.recoveryInterval = interval
+	}
+}
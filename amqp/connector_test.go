@@ -0,0 +1,80 @@
+package amqp
+
+import (
+	"crypto/tls"
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+// TestConnector ensures that RabbitMQ can be provided
+// to the ezQue.Connect function.
+func TestConnector(t *testing.T) {
+	_, _ = ezQue.Connect(RabbitMQ, nil)
+}
+
+// TestDefaults ensures that the connection defaults match a local RabbitMQ.
+func TestDefaults(t *testing.T) {
+	opts := buildConnOptions(Options{})
+
+	require.Equal(t, "localhost", opts.Server)
+	require.Equal(t, uint16(5672), opts.Port)
+	require.Equal(t, "/", opts.VHost)
+	require.Equal(t, defaultRecoveryInterval, opts.recoveryInterval)
+}
+
+// TestConnOptions ensures that the ConnOptionFuncs correctly set the config values
+func TestConnOptions(t *testing.T) {
+	tlsConfig := &tls.Config{}
+	opts := buildConnOptions(Queue("testQueue",
+		AuthenticatedWith("testUser", "testPassword"),
+		LocatedAt("rabbit", 5671),
+		UsingVHost("vhost"),
+		UsingTLS(tlsConfig),
+		PublishingTo("exchange", "key"),
+		WithPrefetch(10),
+		WithRecoveryInterval(time.Minute),
+	)())
+
+	require.Equal(t, "testUser", opts.Username)
+	require.Equal(t, "testPassword", opts.Password)
+	require.Equal(t, "rabbit", opts.Server)
+	require.Equal(t, uint16(5671), opts.Port)
+	require.Equal(t, "vhost", opts.VHost)
+	require.Same(t, tlsConfig, opts.tls)
+	require.Equal(t, "exchange", opts.exchange)
+	require.Equal(t, "key", opts.routingKey)
+	require.Equal(t, 10, opts.prefetch)
+	require.Equal(t, time.Minute, opts.recoveryInterval)
+}
+
+//
+// connect
+//
+
+type ConnectTestSuite struct {
+	suite.Suite
+}
+
+func TestConnectTestSuite(t *testing.T) {
+	suite.Run(t, new(ConnectTestSuite))
+}
+
+func (suite *ConnectTestSuite) Test_FailOnNilOptions() {
+	_, _, err := connect(nil)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyQueueName() {
+	_, _, err := connect(Queue(""))
+	assert.NotNil(suite.T(), err)
+}
+
+// Test_ErrorOnUnreachableBroker tests that connecting fails fast when the broker cannot be reached.
+func (suite *ConnectTestSuite) Test_ErrorOnUnreachableBroker() {
+	_, _, err := connect(Queue("VALID_QUEUE_NAME", LocatedAt("127.0.0.1", 1)))
+	assert.NotNil(suite.T(), err)
+}
@@ -0,0 +1,20 @@
+// Package amqp provides Go functions and types for working with AMQP 0-9-1 brokers, such as RabbitMQ,
+// through the ezQue Queue abstraction.
+//
+// Central to the package is the RabbitMQ function. This function takes in an OptionFunc parameter
+// for customizing the connection options and returns Enqueuer and Dequeuer instances associated
+// with the specified queue.
+//
+// Enqueue publishes with publisher confirms enabled and only returns once the broker has confirmed the
+// message. By default messages are published through the default exchange straight to the queue;
+// PublishingTo selects another exchange and routing key. Dequeue consumes the queue with manual
+// acknowledgements: Ack sends basic.ack and NAck sends basic.nack with requeue set. WithPrefetch bounds
+// the number of unacknowledged messages delivered at a time.
+//
+// Enqueuer and Dequeuer each hold their own connection and channel. When either is lost, it is
+// re-opened on the next operation, retrying every WithRecoveryInterval until the operation's context
+// is done, and a Dequeuer resumes consuming. Messages that were dequeued on the lost channel can no
+// longer be acknowledged; the broker redelivers them.
+//
+// Note: This package relies on "ezQue/api", "ezQue/internal/amqp" and "github.com/rabbitmq/amqp091-go".
+package amqp
@@ -0,0 +1,18 @@
+package api
+
+import "context"
+
+// BatchEnqueuer is an optional interface implemented by Enqueuers whose queue system
+// can enqueue several messages in a single round trip. EnqueueBatch enqueues all of
+// msgs, returning an error if any of them could not be enqueued.
+type BatchEnqueuer[R any] interface {
+	EnqueueBatch(ctx context.Context, msgs []Message[R]) error
+}
+
+// BatchDequeuer is an optional interface implemented by Dequeuers whose queue system
+// can dequeue several messages in a single round trip. DequeueBatch blocks until at
+// least one message is available and returns at most limit messages, each of which
+// must be acknowledged individually.
+type BatchDequeuer[R any] interface {
+	DequeueBatch(ctx context.Context, limit int) ([]DequeueMessage[R], error)
+}
@@ -0,0 +1,21 @@
+package api
+
+import "context"
+
+// Browser is an optional interface implemented by Dequeuers whose queue system can
+// read messages without removing them. Browse returns a BrowseIterator positioned
+// before the first message in the queue.
+type Browser[R any] interface {
+	Browse(ctx context.Context) (BrowseIterator[R], error)
+}
+
+// BrowseIterator walks over the messages in a queue without consuming them. Next
+// advances to the following message, returning false when there are no more messages
+// or an error occurred, which is then returned by Err. Message returns the message
+// Next advanced to. Close must be called once the iterator is no longer needed.
+type BrowseIterator[R any] interface {
+	Next(ctx context.Context) bool
+	Message() Message[R]
+	Err() error
+	Close() error
+}
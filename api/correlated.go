@@ -0,0 +1,11 @@
+package api
+
+// Correlated is an optional interface implemented by Messages that carry a correlation
+// identifier and a reply-to address, as used for request/reply. The reply-to address
+// names the queue that a reply should be enqueued to.
+type Correlated interface {
+	GetCorrelationID() string
+	SetCorrelationID(id string)
+	GetReplyTo() string
+	SetReplyTo(replyTo string)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotSupported is returned when an optional operation, such as browsing, is not
+// supported by the queue system.
+var ErrNotSupported = errors.New("operation not supported by the queue system")
+
+// Errors that queue systems map their own errors onto, so that callers can use
+// errors.Is to decide how to handle a failure. The original error is kept
+// wrapped alongside them.
+var (
+	// ErrTimeout is returned when no message became available in time.
+	ErrTimeout = errors.New("timed out waiting for a message")
+
+	// ErrQueueDisabled is returned when the queue exists but has been stopped
+	// for enqueueing or dequeueing.
+	ErrQueueDisabled = errors.New("queue is disabled")
+
+	// ErrQueueNotFound is returned when the queue does not exist.
+	ErrQueueNotFound = errors.New("queue not found")
+
+	// ErrConnectionLost is returned when the connection to the queue system
+	// was lost.
+	ErrConnectionLost = errors.New("connection lost")
+
+	// ErrPayloadTooLarge is returned when a message is larger than the queue
+	// system accepts.
+	ErrPayloadTooLarge = errors.New("payload too large")
+
+	// ErrUnauthorized is returned when the credentials are invalid or lack
+	// the privileges for the operation.
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// ErrCircuitOpen is returned without calling the queue system when a circuit breaker
+// has opened after repeated failures.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Retryable reports whether err is a transient failure, a timeout or a lost
+// connection, that may succeed if the operation is retried.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, ErrConnectionLost)
+}
+
+// ErrorCategory returns a short name for the kind of err, such as "timeout" or
+// "connection_lost", for use as a metric label or log attribute. Errors that do not
+// match one of the errors of this package are "other".
+func ErrorCategory(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrQueueDisabled):
+		return "queue_disabled"
+	case errors.Is(err, ErrQueueNotFound):
+		return "queue_not_found"
+	case errors.Is(err, ErrConnectionLost):
+		return "connection_lost"
+	case errors.Is(err, ErrPayloadTooLarge):
+		return "payload_too_large"
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, ErrNotSupported):
+		return "not_supported"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,10 @@
+package api
+
+import "context"
+
+// HealthChecker is an optional interface implemented by Enqueuers and Dequeuers that
+// can check whether their connection to the queue system is usable. Health returns
+// nil if it is, and otherwise the error, such as ErrConnectionLost.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
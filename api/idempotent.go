@@ -0,0 +1,11 @@
+package api
+
+// Idempotent is an optional interface implemented by Messages whose queue system can
+// enqueue a message at most once per idempotency key, such as OracleAQ with a key
+// table, SQS FIFO queues and JetStream. Enqueueing a message again with the same key,
+// for example after a commit timeout left it unclear whether the first enqueue
+// succeeded, succeeds without enqueueing a duplicate.
+type Idempotent interface {
+	GetIdempotencyKey() string
+	SetIdempotencyKey(key string)
+}
@@ -0,0 +1,8 @@
+package api
+
+// Identified is an optional interface implemented by Messages that carry an identifier
+// assigned by the queue system, such as the message ID of a dequeued OracleAQ message.
+// GetMessageID returns an empty string if the message has no identifier yet.
+type Identified interface {
+	GetMessageID() string
+}
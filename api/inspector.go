@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// Stats is a snapshot of the messages in a queue. Queue systems fill in the counts
+// they can provide and leave the others zero.
+type Stats struct {
+
+	// Ready is the number of messages available for dequeueing.
+	Ready int64
+
+	// Waiting is the number of messages that are delayed, or waiting to be retried,
+	// and cannot be dequeued yet.
+	Waiting int64
+
+	// Expired is the number of messages that expired or ran out of retries, and were
+	// moved to the exception (dead-letter) queue.
+	Expired int64
+
+	// OldestEnqueued is when the oldest ready message was enqueued. It is the zero
+	// time if there are no ready messages.
+	OldestEnqueued time.Time
+}
+
+// Inspector is an optional interface implemented by Dequeuers whose queue system can
+// report how many messages are in the queue.
+type Inspector interface {
+	Stats(ctx context.Context) (Stats, error)
+}
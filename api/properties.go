@@ -0,0 +1,10 @@
+package api
+
+// Properties is an optional interface implemented by Messages that carry string
+// properties, or headers, alongside their content. They are used, for example, to
+// propagate the trace a message was enqueued in to its consumer.
+type Properties interface {
+	GetProperty(key string) string
+	SetProperty(key string, value string)
+	PropertyKeys() []string
+}
@@ -0,0 +1,26 @@
+package api
+
+import "context"
+
+// Selector identifies the message to dequeue when a specific message is wanted rather
+// than whatever is next, such as the reply to a request. Empty fields are ignored, and
+// a message must match all the fields that are set.
+type Selector struct {
+
+	// MessageID selects the message with this queue system specific identifier.
+	MessageID []byte
+
+	// CorrelationID selects messages with this correlation identifier.
+	CorrelationID string
+
+	// Condition selects messages matching a queue system specific predicate, such as
+	// a SQL condition on the message properties and payload for Oracle AQ.
+	Condition string
+}
+
+// SelectiveDequeuer is an optional interface implemented by Dequeuers whose queue system
+// can dequeue a specific message. DequeueWhere blocks until a message matching sel is
+// available or the context is cancelled.
+type SelectiveDequeuer[R any] interface {
+	DequeueWhere(ctx context.Context, sel Selector) (DequeueMessage[R], error)
+}
@@ -0,0 +1,10 @@
+package api
+
+import "time"
+
+// Timestamped is an optional interface implemented by Messages that carry the time the
+// queue system accepted them, such as the record timestamp of a dequeued Kafka message.
+// GetEnqueuedAt returns the zero time if the message has not been dequeued.
+type Timestamped interface {
+	GetEnqueuedAt() time.Time
+}
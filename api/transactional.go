@@ -0,0 +1,11 @@
+package api
+
+import "database/sql"
+
+// Transactional is an optional interface implemented by DequeueMessages that are
+// dequeued within a database transaction, such as those of OracleAQ. Ack commits the
+// transaction and NAck rolls it back, so work done in Tx is committed together with
+// the dequeue, or not at all.
+type Transactional interface {
+	Tx() *sql.Tx
+}
@@ -0,0 +1,164 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+// EnqueueBatch enqueues msgs onto q. If q supports api.BatchEnqueuer the messages
+// are enqueued in batches, otherwise they are enqueued one at a time. It returns
+// the first error encountered.
+func EnqueueBatch[R any](ctx context.Context, q Queue[R], msgs []api.Message[R]) error {
+
+	if batcher, ok := q.(api.BatchEnqueuer[R]); ok {
+		return batcher.EnqueueBatch(ctx, msgs)
+	}
+
+	for _, msg := range msgs {
+		err := q.Enqueue(ctx, msg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DequeueBatch dequeues up to limit messages from q, blocking until at least one is
+// available. If q does not support api.BatchDequeuer a single message is dequeued.
+func DequeueBatch[R any](ctx context.Context, q Queue[R], limit int) ([]api.DequeueMessage[R], error) {
+
+	if batcher, ok := q.(api.BatchDequeuer[R]); ok {
+		return batcher.DequeueBatch(ctx, limit)
+	}
+
+	msg, err := q.Dequeue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []api.DequeueMessage[R]{msg}, nil
+}
+
+// EnqueueBatch delegates to the enqueuer if it supports api.BatchEnqueuer, and
+// otherwise enqueues the messages one at a time. Every message passes through the
+// enqueue interceptors, and the messages they let through are enqueued in a single
+// batch.
+func (q *queue[R]) EnqueueBatch(ctx context.Context, msgs []api.Message[R]) error {
+
+	if batcher, ok := q.enqueuer.(api.BatchEnqueuer[R]); ok {
+		return q.interceptEnqueueBatch(ctx, msgs, batcher)
+	}
+
+	for _, msg := range msgs {
+		err := q.Enqueue(ctx, msg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DequeueBatch delegates to the dequeuer if it supports api.BatchDequeuer, and
+// otherwise dequeues a single message. Every message of the batch passes through
+// the dequeue interceptors.
+func (q *queue[R]) DequeueBatch(ctx context.Context, limit int) ([]api.DequeueMessage[R], error) {
+
+	if batcher, ok := q.dequeuer.(api.BatchDequeuer[R]); ok {
+		return q.interceptDequeueBatch(ctx, limit, batcher)
+	}
+
+	msg, err := q.Dequeue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []api.DequeueMessage[R]{msg}, nil
+}
+
+// interceptEnqueueBatch runs every message through the enqueue interceptors. The
+// interceptors of each message are nested within those of the message before it,
+// and the innermost call enqueues all messages that reached it with batcher, so
+// that each interceptor sees the outcome of the batch. It returns the first error.
+func (q *queue[R]) interceptEnqueueBatch(ctx context.Context, msgs []api.Message[R], batcher api.BatchEnqueuer[R]) error {
+
+	batch := make([]api.Message[R], 0, len(msgs))
+	errs := make([]error, len(msgs))
+	var batchErr error
+
+	var enqueueFrom func(i int)
+	enqueueFrom = func(i int) {
+		for ; i < len(msgs); i++ {
+			reached := false
+			errs[i] = q.interceptEnqueue(ctx, msgs[i], func(ctx context.Context, msg api.Message[R]) error {
+				if reached {
+					// an interceptor calling next again, for example to retry,
+					// enqueues the message by itself
+					return q.enqueuer.Enqueue(ctx, msg)
+				}
+				reached = true
+				batch = append(batch, msg)
+				enqueueFrom(i + 1)
+				return batchErr
+			})
+			if reached {
+				return
+			}
+		}
+		if len(batch) > 0 {
+			batchErr = batcher.EnqueueBatch(ctx, batch)
+		}
+	}
+	enqueueFrom(0)
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interceptDequeueBatch dequeues a batch with batcher within the dequeue
+// interceptors of its first message, and then runs every other message through
+// the dequeue interceptors. Messages an interceptor fails are left out, and the
+// first error is returned along with the remaining messages.
+func (q *queue[R]) interceptDequeueBatch(ctx context.Context, limit int, batcher api.BatchDequeuer[R]) ([]api.DequeueMessage[R], error) {
+
+	var rest []api.DequeueMessage[R]
+	var batchErr error
+	first, firstErr := q.interceptDequeue(ctx, func(ctx context.Context) (api.DequeueMessage[R], error) {
+		msgs, err := batcher.DequeueBatch(ctx, limit)
+		if len(msgs) == 0 {
+			return nil, err
+		}
+		rest, batchErr = append(rest, msgs[1:]...), err
+		return msgs[0], nil
+	})
+
+	msgs := make([]api.DequeueMessage[R], 0, len(rest)+1)
+	if firstErr == nil {
+		msgs = append(msgs, first)
+	}
+	for _, msg := range rest {
+		intercepted, err := q.interceptDequeue(ctx, func(context.Context) (api.DequeueMessage[R], error) {
+			return msg, nil
+		})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		msgs = append(msgs, intercepted)
+	}
+	if firstErr == nil {
+		firstErr = batchErr
+	}
+
+	if len(msgs) == 0 {
+		return nil, firstErr
+	}
+	return msgs, firstErr
+}
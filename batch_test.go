@@ -0,0 +1,203 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"reflect"
+	"testing"
+)
+
+// fakeMessage is a minimal api.Message and api.DequeueMessage over a string.
+type fakeMessage struct {
+	text string
+}
+
+func (m *fakeMessage) Raw() string                  { return m.text }
+func (m *fakeMessage) Text() string                 { return m.text }
+func (m *fakeMessage) SetRaw(raw string)            { m.text = raw }
+func (m *fakeMessage) SetText(text string)          { m.text = text }
+func (m *fakeMessage) Message() api.Message[string] { return m }
+func (m *fakeMessage) Ack(context.Context) error    { return nil }
+func (m *fakeMessage) NAck(context.Context) error   { return nil }
+
+// fakeQueue is an in-memory enqueuer and dequeuer without batch support.
+type fakeQueue struct {
+	msgs []string
+}
+
+func (q *fakeQueue) NewMessage() api.Message[string] { return &fakeMessage{} }
+func (q *fakeQueue) Enqueue(_ context.Context, msg api.Message[string]) error {
+	q.msgs = append(q.msgs, msg.Text())
+	return nil
+}
+func (q *fakeQueue) Dequeue(ctx context.Context) (api.DequeueMessage[string], error) {
+	if len(q.msgs) == 0 {
+		return nil, context.DeadlineExceeded
+	}
+	msg := &fakeMessage{text: q.msgs[0]}
+	q.msgs = q.msgs[1:]
+	return msg, nil
+}
+func (q *fakeQueue) Disconnect(context.Context) error { return nil }
+
+// TestBatchFallback ensures that the batch helpers fall back to single messages
+// when the queue system does not support batching.
+func TestBatchFallback(t *testing.T) {
+	fq := &fakeQueue{}
+	q, err := Connect(func(struct{}) (api.Enqueuer[string], api.Dequeuer[string], error) {
+		return fq, fq, nil
+	}, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	err = EnqueueBatch(ctx, q, []api.Message[string]{&fakeMessage{text: "a"}, &fakeMessage{text: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fq.msgs) != 2 {
+		t.Fatalf("expected 2 enqueued messages, got %d", len(fq.msgs))
+	}
+
+	batch, err := DequeueBatch(ctx, q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 1 || batch[0].Message().Text() != "a" {
+		t.Fatalf("expected a single message \"a\", got %v", batch)
+	}
+}
+
+// batchQueue is a fakeQueue with batch support, which counts its batch calls.
+type batchQueue struct {
+	fakeQueue
+	batches  int
+	batchErr error
+}
+
+func (q *batchQueue) EnqueueBatch(_ context.Context, msgs []api.Message[string]) error {
+	q.batches++
+	if q.batchErr != nil {
+		return q.batchErr
+	}
+	for _, msg := range msgs {
+		q.msgs = append(q.msgs, msg.Text())
+	}
+	return nil
+}
+
+func (q *batchQueue) DequeueBatch(_ context.Context, limit int) ([]api.DequeueMessage[string], error) {
+	q.batches++
+	var batch []api.DequeueMessage[string]
+	for len(q.msgs) > 0 && len(batch) < limit {
+		batch = append(batch, &fakeMessage{text: q.msgs[0]})
+		q.msgs = q.msgs[1:]
+	}
+	if len(batch) == 0 {
+		return nil, context.DeadlineExceeded
+	}
+	return batch, nil
+}
+
+// connectBatch connects a Queue over fake with the given ConnectOptions.
+func connectBatch(t *testing.T, fake *batchQueue, opts ...ConnectOption[string]) Queue[string] {
+	q, err := Connect(func(struct{}) (api.Enqueuer[string], api.Dequeuer[string], error) {
+		return fake, fake, nil
+	}, struct{}{}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+// TestEnqueueBatchIntercepted ensures that interceptors do not split a batch: every
+// message passes through them, and those they let through are enqueued in a single
+// batch whose outcome they see.
+func TestEnqueueBatchIntercepted(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	errBatch := errors.New("batch failed")
+	var outcomes []string
+	fake := &batchQueue{}
+	q := connectBatch(t, fake, WithEnqueueInterceptors(
+		func(ctx context.Context, msg api.Message[string], next EnqueueFunc[string]) error {
+			if msg.Text() == "invalid" {
+				return errInvalid
+			}
+			err := next(ctx, msg)
+			outcomes = append(outcomes, fmt.Sprintf("%s: %v", msg.Text(), err))
+			return err
+		},
+	))
+
+	ctx := context.Background()
+	err := EnqueueBatch(ctx, q, []api.Message[string]{
+		&fakeMessage{text: "a"}, &fakeMessage{text: "invalid"}, &fakeMessage{text: "b"},
+	})
+	if !errors.Is(err, errInvalid) {
+		t.Fatalf("expected the interceptor error, got %v", err)
+	}
+	if fake.batches != 1 || !reflect.DeepEqual(fake.msgs, []string{"a", "b"}) {
+		t.Fatalf("expected a single batch of a and b, got %d batches of %v", fake.batches, fake.msgs)
+	}
+	if !reflect.DeepEqual(outcomes, []string{"b: <nil>", "a: <nil>"}) {
+		t.Fatalf("unexpected interceptor outcomes %v", outcomes)
+	}
+
+	outcomes = nil
+	fake.batchErr = errBatch
+	err = EnqueueBatch(ctx, q, []api.Message[string]{&fakeMessage{text: "c"}, &fakeMessage{text: "d"}})
+	if !errors.Is(err, errBatch) {
+		t.Fatalf("expected the batch error, got %v", err)
+	}
+	if fake.batches != 2 || !reflect.DeepEqual(outcomes, []string{"d: batch failed", "c: batch failed"}) {
+		t.Fatalf("unexpected interceptor outcomes %v after %d batches", outcomes, fake.batches)
+	}
+}
+
+// TestDequeueBatchIntercepted ensures that interceptors do not split a batch: it is
+// dequeued in a single call, and every message passes through the dequeue and ack
+// interceptors.
+func TestDequeueBatchIntercepted(t *testing.T) {
+	var calls []string
+	fake := &batchQueue{fakeQueue: fakeQueue{msgs: []string{"a", "b", "c"}}}
+	q := connectBatch(t, fake,
+		WithDequeueInterceptors(func(ctx context.Context, next DequeueFunc[string]) (api.DequeueMessage[string], error) {
+			msg, err := next(ctx)
+			if err == nil {
+				calls = append(calls, "dequeue "+msg.Message().Text())
+			}
+			return msg, err
+		}),
+		WithAckInterceptors(func(ctx context.Context, msg api.DequeueMessage[string], ack bool, next AckFunc[string]) error {
+			calls = append(calls, "ack "+msg.Message().Text())
+			return next(ctx, msg)
+		}),
+	)
+
+	ctx := context.Background()
+	batch, err := DequeueBatch(ctx, q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.batches != 1 || len(batch) != 3 {
+		t.Fatalf("expected a single batch of 3 messages, got %d batches of %d", fake.batches, len(batch))
+	}
+	for _, msg := range batch {
+		if err := msg.Ack(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{"dequeue a", "dequeue b", "dequeue c", "ack a", "ack b", "ack c"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("unexpected interceptor calls %v", calls)
+	}
+
+	_, err = DequeueBatch(ctx, q, 10)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the batch error, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+// Browse returns an iterator over the messages in q that leaves them in the queue.
+// It returns api.ErrNotSupported if q does not support api.Browser.
+func Browse[R any](ctx context.Context, q Queue[R]) (api.BrowseIterator[R], error) {
+
+	browser, ok := q.(api.Browser[R])
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	return browser.Browse(ctx)
+}
+
+// Peek returns the first message in q without removing it, or nil if q is empty.
+// It returns api.ErrNotSupported if q does not support api.Browser.
+func Peek[R any](ctx context.Context, q Queue[R]) (api.Message[R], error) {
+
+	it, err := Browse(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = it.Close() }()
+
+	if !it.Next(ctx) {
+		return nil, it.Err()
+	}
+
+	return it.Message(), nil
+}
+
+// Browse delegates to the dequeuer if it supports api.Browser, and otherwise
+// returns api.ErrNotSupported.
+func (q *queue[R]) Browse(ctx context.Context) (api.BrowseIterator[R], error) {
+
+	browser, ok := q.dequeuer.(api.Browser[R])
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	return browser.Browse(ctx)
+}
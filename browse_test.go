@@ -0,0 +1,71 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"testing"
+)
+
+// fakeBrowser is a fakeQueue that supports api.Browser.
+type fakeBrowser struct {
+	fakeQueue
+}
+
+func (q *fakeBrowser) Browse(context.Context) (api.BrowseIterator[string], error) {
+	return &fakeIterator{msgs: q.msgs}, nil
+}
+
+// fakeIterator walks over a copy of the fakeBrowser's messages.
+type fakeIterator struct {
+	msgs    []string
+	current fakeMessage
+}
+
+func (it *fakeIterator) Next(context.Context) bool {
+	if len(it.msgs) == 0 {
+		return false
+	}
+	it.current.text, it.msgs = it.msgs[0], it.msgs[1:]
+	return true
+}
+func (it *fakeIterator) Message() api.Message[string] { return &it.current }
+func (it *fakeIterator) Err() error                   { return nil }
+func (it *fakeIterator) Close() error                 { return nil }
+
+// TestPeek ensures that Peek returns the first message without removing it.
+func TestPeek(t *testing.T) {
+	fb := &fakeBrowser{fakeQueue{msgs: []string{"a", "b"}}}
+	q, err := Connect(func(struct{}) (api.Enqueuer[string], api.Dequeuer[string], error) {
+		return fb, fb, nil
+	}, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := Peek(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Text() != "a" {
+		t.Fatalf("expected \"a\", got %q", msg.Text())
+	}
+	if len(fb.msgs) != 2 {
+		t.Fatalf("expected Peek to leave 2 messages, got %d", len(fb.msgs))
+	}
+}
+
+// TestBrowseNotSupported ensures that Browse reports queue systems without browse support.
+func TestBrowseNotSupported(t *testing.T) {
+	fq := &fakeQueue{}
+	q, err := Connect(func(struct{}) (api.Enqueuer[string], api.Dequeuer[string], error) {
+		return fq, fq, nil
+	}, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Peek(context.Background(), q)
+	if err != api.ErrNotSupported {
+		t.Fatalf("expected api.ErrNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	"sync"
+	"time"
+)
+
+// Defaults of the CircuitBreakerOptions.
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+	defaultHalfOpenProbes   = 1
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets operations through, counting consecutive failures.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails operations with api.ErrCircuitOpen without calling the
+	// queue system.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of probes through, closing the circuit
+	// if they succeed and opening it again if one fails.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOptions holds the settings of a CircuitBreaker.
+type CircuitBreakerOptions struct {
+
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. It defaults to 5.
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before half-opening to
+	// probe the queue system. It defaults to 30s.
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of enqueues, Acks and NAcks let through at a
+	// time while the circuit is half-open. It defaults to 1.
+	HalfOpenProbes int
+
+	// IsFailure reports whether an error counts as a failure of the queue system.
+	// It defaults to every error except the context being done and
+	// api.ErrPayloadTooLarge, which are caused by the caller, and api.ErrTimeout,
+	// which is returned when a dequeue finds the queue empty.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called when the circuit changes state.
+	OnStateChange func(from CircuitState, to CircuitState)
+}
+
+// CircuitBreaker stops calling a queue system that keeps failing, so that it is not
+// overloaded further. It is safe for concurrent use, and can be shared by Queues
+// connected to the same queue system.
+type CircuitBreaker struct {
+	opts CircuitBreakerOptions
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+
+	// generation is incremented on every state change, so that the outcome of an
+	// operation that started in an earlier state is ignored.
+	generation uint64
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker.
+func NewCircuitBreaker(opts CircuitBreakerOptions) *CircuitBreaker {
+
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaultFailureThreshold
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = defaultOpenTimeout
+	}
+	if opts.HalfOpenProbes <= 0 {
+		opts.HalfOpenProbes = defaultHalfOpenProbes
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = isQueueFailure
+	}
+
+	return &CircuitBreaker{
+		opts: opts,
+		now:  time.Now,
+	}
+}
+
+// State returns the state of the circuit, for monitoring.
+func (cb *CircuitBreaker) State() CircuitState {
+
+	cb.mu.Lock()
+	from, to := cb.advance()
+	state := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+	return state
+}
+
+// WithCircuitBreaker returns a Queue whose enqueues, dequeues, Acks and NAcks pass
+// through breaker, failing with api.ErrCircuitOpen while it is open. While it is
+// half-open, dequeues are let through without counting as probes, since a dequeue
+// may wait a long time for a message, but their outcome still closes or opens the
+// circuit.
+func WithCircuitBreaker[R any](q Queue[R], breaker *CircuitBreaker) Queue[R] {
+
+	c := &circuit[R]{breaker: breaker}
+
+	inner, ok := q.(*queue[R])
+	if !ok {
+		inner = &queue[R]{enqueuer: q, dequeuer: disconnected[R]{q}}
+	}
+
+	// The circuit breaker is the outermost interceptor, so that it fails fast
+	broken := *inner
+	broken.enqueueInterceptors = append([]EnqueueInterceptor[R]{c.enqueue}, inner.enqueueInterceptors...)
+	broken.dequeueInterceptors = append([]DequeueInterceptor[R]{c.dequeue}, inner.dequeueInterceptors...)
+	broken.ackInterceptors = append([]AckInterceptor[R]{c.ack}, inner.ackInterceptors...)
+
+	return &broken
+}
+
+// circuit holds the interceptors that pass operations through a CircuitBreaker.
+type circuit[R any] struct {
+	breaker *CircuitBreaker
+}
+
+func (c *circuit[R]) enqueue(ctx context.Context, msg api.Message[R], next EnqueueFunc[R]) error {
+
+	done, err := c.breaker.acquire(true)
+	if err != nil {
+		return err
+	}
+
+	err = next(ctx, msg)
+	done(err)
+	return err
+}
+
+func (c *circuit[R]) dequeue(ctx context.Context, next DequeueFunc[R]) (api.DequeueMessage[R], error) {
+
+	done, err := c.breaker.acquire(false)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := next(ctx)
+	done(err)
+	return msg, err
+}
+
+func (c *circuit[R]) ack(ctx context.Context, msg api.DequeueMessage[R], _ bool, next AckFunc[R]) error {
+
+	done, err := c.breaker.acquire(true)
+	if err != nil {
+		return err
+	}
+
+	err = next(ctx, msg)
+	done(err)
+	return err
+}
+
+// acquire reports whether an operation may run, returning api.ErrCircuitOpen if not.
+// Probes take one of the HalfOpenProbes while the circuit is half-open. The returned
+// function records the outcome of the operation.
+func (cb *CircuitBreaker) acquire(probe bool) (func(err error), error) {
+
+	cb.mu.Lock()
+	from, to := cb.advance()
+
+	tookProbe := false
+	generation := cb.generation
+	switch cb.state {
+	case CircuitOpen:
+		cb.mu.Unlock()
+		cb.notify(from, to)
+		return nil, api.ErrCircuitOpen
+	case CircuitHalfOpen:
+		if probe {
+			if cb.probes >= cb.opts.HalfOpenProbes {
+				cb.mu.Unlock()
+				cb.notify(from, to)
+				return nil, api.ErrCircuitOpen
+			}
+			cb.probes++
+			tookProbe = true
+		}
+	}
+	cb.mu.Unlock()
+	cb.notify(from, to)
+
+	return func(err error) {
+		cb.record(err, generation, tookProbe)
+	}, nil
+}
+
+// record updates the circuit with the outcome of an operation that started in the
+// given generation. Outcomes of operations that started before the last state change,
+// such as a long dequeue that started while the circuit was closed, are ignored.
+func (cb *CircuitBreaker) record(err error, generation uint64, tookProbe bool) {
+
+	cb.mu.Lock()
+	if generation != cb.generation {
+		cb.mu.Unlock()
+		return
+	}
+	if tookProbe {
+		cb.probes--
+	}
+
+	from, to := cb.state, cb.state
+	failed := err != nil && cb.opts.IsFailure(err)
+	switch {
+	case failed && cb.state == CircuitHalfOpen:
+		to = cb.open()
+	case failed && cb.state == CircuitClosed:
+		cb.failures++
+		if cb.failures >= cb.opts.FailureThreshold {
+			to = cb.open()
+		}
+	case !failed && cb.state == CircuitHalfOpen:
+		cb.state, cb.failures = CircuitClosed, 0
+		cb.generation++
+		to = CircuitClosed
+	case !failed && cb.state == CircuitClosed:
+		cb.failures = 0
+	}
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+}
+
+// open opens the circuit. It must be called with mu held.
+func (cb *CircuitBreaker) open() CircuitState {
+	cb.state = CircuitOpen
+	cb.openedAt = cb.now()
+	cb.failures = 0
+	cb.generation++
+	return CircuitOpen
+}
+
+// advance half-opens the circuit once it has been open for OpenTimeout, returning
+// the state change. It must be called with mu held.
+func (cb *CircuitBreaker) advance() (CircuitState, CircuitState) {
+
+	from := cb.state
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.opts.OpenTimeout {
+		cb.state = CircuitHalfOpen
+		cb.probes = 0
+		cb.generation++
+	}
+
+	return from, cb.state
+}
+
+// notify calls OnStateChange if the state changed. It must be called without mu held.
+func (cb *CircuitBreaker) notify(from CircuitState, to CircuitState) {
+	if from != to && cb.opts.OnStateChange != nil {
+		cb.opts.OnStateChange(from, to)
+	}
+}
+
+// isQueueFailure is the default IsFailure, counting every error that is not caused
+// by the caller or by the queue being empty.
+func isQueueFailure(err error) bool {
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, api.ErrPayloadTooLarge) &&
+		!errors.Is(err, api.ErrTimeout)
+}
+
+// disconnected is a Dequeuer whose Disconnect does nothing, used when a Queue that
+// was not returned by Connect is both the enqueuer and the dequeuer of a queue, so
+// that it is only disconnected once.
+type disconnected[R any] struct {
+	api.Dequeuer[R]
+}
+
+func (d disconnected[R]) Disconnect(context.Context) error {
+	return nil
+}
@@ -0,0 +1,183 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"testing"
+	"time"
+)
+
+// newTestBreaker returns a CircuitBreaker with a clock the test advances.
+func newTestBreaker(opts CircuitBreakerOptions) (*CircuitBreaker, *time.Time) {
+	clock := time.Unix(0, 0)
+	cb := NewCircuitBreaker(opts)
+	cb.now = func() time.Time { return clock }
+	return cb, &clock
+}
+
+// TestCircuitBreaker ensures that the circuit opens after consecutive failures, fails
+// fast, half-opens after the timeout and closes when a probe succeeds.
+func TestCircuitBreaker(t *testing.T) {
+	var changes []string
+	cb, clock := newTestBreaker(CircuitBreakerOptions{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+		OnStateChange: func(from, to CircuitState) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+	fake := &flakyQueue{failures: 3, err: api.ErrConnectionLost}
+	q := WithCircuitBreaker(connectFake[string](t, fake), cb)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(ctx, &fakeMessage{text: "a"}); !errors.Is(err, api.ErrConnectionLost) {
+			t.Fatalf("expected api.ErrConnectionLost, got %v", err)
+		}
+	}
+	if cb.State() != CircuitOpen {
+		t.Fatalf("expected the circuit to be open, got %v", cb.State())
+	}
+
+	// An open circuit fails fast without calling the queue system
+	if err := q.Enqueue(ctx, &fakeMessage{text: "a"}); !errors.Is(err, api.ErrCircuitOpen) {
+		t.Fatalf("expected api.ErrCircuitOpen, got %v", err)
+	}
+	if _, err := q.Dequeue(ctx); !errors.Is(err, api.ErrCircuitOpen) {
+		t.Fatalf("expected api.ErrCircuitOpen, got %v", err)
+	}
+	if fake.attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", fake.attempts)
+	}
+
+	// A failed probe opens the circuit again
+	*clock = clock.Add(time.Minute)
+	if cb.State() != CircuitHalfOpen {
+		t.Fatalf("expected the circuit to be half-open, got %v", cb.State())
+	}
+	if err := q.Enqueue(ctx, &fakeMessage{text: "a"}); !errors.Is(err, api.ErrConnectionLost) {
+		t.Fatalf("expected api.ErrConnectionLost, got %v", err)
+	}
+	if cb.State() != CircuitOpen {
+		t.Fatalf("expected the circuit to be open, got %v", cb.State())
+	}
+
+	// A successful probe closes it
+	*clock = clock.Add(time.Minute)
+	if err := q.Enqueue(ctx, &fakeMessage{text: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if cb.State() != CircuitClosed {
+		t.Fatalf("expected the circuit to be closed, got %v", cb.State())
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("unexpected state changes %v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("unexpected state changes %v", changes)
+		}
+	}
+}
+
+// TestCircuitBreakerCallerErrors ensures that errors caused by the caller do not open
+// the circuit.
+func TestCircuitBreakerCallerErrors(t *testing.T) {
+	cb, _ := newTestBreaker(CircuitBreakerOptions{FailureThreshold: 1})
+	fake := &flakyQueue{failures: 1, err: api.ErrPayloadTooLarge}
+	q := WithCircuitBreaker(connectFake[string](t, fake), cb)
+
+	_ = q.Enqueue(context.Background(), &fakeMessage{text: "a"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = q.Dequeue(ctx)
+
+	if cb.State() != CircuitClosed {
+		t.Fatalf("expected the circuit to be closed, got %v", cb.State())
+	}
+}
+
+// TestCircuitBreakerHalfOpenProbes ensures that only HalfOpenProbes enqueues are let
+// through at a time while the circuit is half-open.
+func TestCircuitBreakerHalfOpenProbes(t *testing.T) {
+	cb, clock := newTestBreaker(CircuitBreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second})
+
+	done, err := cb.acquire(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(api.ErrConnectionLost)
+	*clock = clock.Add(time.Second)
+
+	probe, err := cb.acquire(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cb.acquire(true); !errors.Is(err, api.ErrCircuitOpen) {
+		t.Fatalf("expected a second probe to be refused, got %v", err)
+	}
+	if _, err := cb.acquire(false); err != nil {
+		t.Fatalf("expected a dequeue to be let through, got %v", err)
+	}
+	probe(nil)
+
+	if cb.State() != CircuitClosed {
+		t.Fatalf("expected the circuit to be closed, got %v", cb.State())
+	}
+}
+
+// emptyQueue is a fakeQueue whose dequeues time out as an empty OracleAQ queue does.
+type emptyQueue struct {
+	fakeQueue
+}
+
+func (q *emptyQueue) Dequeue(context.Context) (api.DequeueMessage[string], error) {
+	return nil, fmt.Errorf("%w: ORA-25228: timeout or end-of-fetch during message dequeue", api.ErrTimeout)
+}
+
+// TestCircuitBreakerDequeueTimeouts ensures that dequeues timing out on an empty queue
+// do not open the circuit.
+func TestCircuitBreakerDequeueTimeouts(t *testing.T) {
+	cb, _ := newTestBreaker(CircuitBreakerOptions{FailureThreshold: 2})
+	q := WithCircuitBreaker(connectFake[string](t, &emptyQueue{}), cb)
+
+	for i := 0; i < 5; i++ {
+		if _, err := q.Dequeue(context.Background()); !errors.Is(err, api.ErrTimeout) {
+			t.Fatalf("expected api.ErrTimeout, got %v", err)
+		}
+	}
+
+	if cb.State() != CircuitClosed {
+		t.Fatalf("expected the circuit to be closed, got %v", cb.State())
+	}
+}
+
+// TestCircuitBreakerStaleOutcome ensures that the outcome of an operation that started
+// before the circuit opened does not close the half-open circuit.
+func TestCircuitBreakerStaleOutcome(t *testing.T) {
+	cb, clock := newTestBreaker(CircuitBreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second})
+
+	dequeue, err := cb.acquire(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed, err := cb.acquire(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed(api.ErrConnectionLost)
+	*clock = clock.Add(time.Second)
+	if cb.State() != CircuitHalfOpen {
+		t.Fatalf("expected the circuit to be half-open, got %v", cb.State())
+	}
+
+	dequeue(nil)
+
+	if cb.State() != CircuitHalfOpen {
+		t.Fatalf("expected the circuit to stay half-open, got %v", cb.State())
+	}
+}
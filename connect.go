@@ -1,7 +1,9 @@
 package ezQue
 
 import (
+	"context"
 	"github.com/pgvanniekerk/ezQue/api"
+	"log/slog"
 )
 
 // queueConnector is a type alias for a function that constructs and initializes Enqueuer and Dequeuer objects.
@@ -25,17 +27,29 @@ type queueConnector[R, O any] func(options O) (api.Enqueuer[R], api.Dequeuer[R],
 // implementation using the provided Enqueuer and Dequeuer. It then returns the newly created Queue and a nil value
 // for the error.
 //
+// ConnectOptions, such as WithEnqueueInterceptors, add interceptors that are called around the
+// Enqueuer and Dequeuer, whichever queue system they belong to.
+//
 // Callers should use this function when they want to set up a Queue with specific Enqueuer and Dequeuer implementations.
-func Connect[R, O any](qc queueConnector[R, O], options O) (Queue[R], error) {
+func Connect[R, O any](qc queueConnector[R, O], options O, connOpts ...ConnectOption[R]) (Queue[R], error) {
+
+	q := &queue[R]{}
+	for _, opt := range connOpts {
+		opt(q)
+	}
 
 	enqueuer, dequeuer, err := qc(options)
 	if err != nil {
+		if q.logger != nil {
+			q.logger.LogAttrs(context.Background(), slog.LevelError, "queue connect failed", errorAttrs(err)...)
+		}
 		return nil, err
 	}
 
-	q := &queue[R]{
-		enqueuer: enqueuer,
-		dequeuer: dequeuer,
+	q.enqueuer = enqueuer
+	q.dequeuer = dequeuer
+	if q.logger != nil {
+		q.logger.LogAttrs(context.Background(), slog.LevelInfo, "queue connected")
 	}
 
 	return q, nil
@@ -0,0 +1,229 @@
+package dedup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/pgvanniekerk/ezQue/api"
+	"time"
+)
+
+// Defaults of WithSeenRetry.
+const (
+	defaultSeenAttempts = 3
+	defaultSeenBackoff  = 100 * time.Millisecond
+)
+
+// ErrAlreadyRecorded is returned by Record when the key was recorded by another
+// consumer that processed a message with the same key at the same time. If Record was
+// given a transaction it has been rolled back, so the work done in it is discarded
+// and the message is delivered again, to be skipped as a duplicate.
+var ErrAlreadyRecorded = errors.New("dedup: key already recorded")
+
+// Store records the keys of the messages that have been processed. Implementations
+// must be safe for concurrent use.
+type Store interface {
+
+	// Seen reports whether key has been recorded. tx is the transaction the message
+	// was dequeued in, or nil if it was not dequeued in one.
+	Seen(ctx context.Context, tx *sql.Tx, key string) (bool, error)
+
+	// Record records key. tx is the transaction the message was dequeued in, or nil
+	// if it was not dequeued in one. It returns an error wrapping ErrAlreadyRecorded,
+	// after rolling back tx, if key was recorded concurrently.
+	Record(ctx context.Context, tx *sql.Tx, key string) error
+}
+
+// Options holds how the key of a message is read, and how reading the Store is
+// retried.
+type Options[R any] struct {
+	key          func(msg api.Message[R]) string
+	seenAttempts int
+	seenBackoff  time.Duration
+}
+
+// OptionFunc is a function type to set Options.
+type OptionFunc[R any] func(*Options[R])
+
+// WithKey reads the key of a message with key, such as an idempotency key the producer
+// set in the message properties. Messages whose key is empty are not deduplicated.
+func WithKey[R any](key func(msg api.Message[R]) string) OptionFunc[R] {
+	return func(opts *Options[R]) {
+		opts.key = key
+	}
+}
+
+// WithSeenRetry sets how many times checking the Store for the key of a message is
+// attempted, waiting backoff after the first failure and doubling the wait after
+// each further one, before the message is handed back with NAck and Dequeue fails.
+// It defaults to 3 attempts, starting with 100ms.
+func WithSeenRetry[R any](attempts int, backoff time.Duration) OptionFunc[R] {
+	return func(opts *Options[R]) {
+		opts.seenAttempts = attempts
+		opts.seenBackoff = backoff
+	}
+}
+
+// Interceptors returns the interceptors that skip duplicate messages recorded in
+// store, to be passed to ezQue.Connect with ezQue.WithInterceptors. By default a
+// message is identified by its message ID.
+func Interceptors[R any](store Store, optFuncs ...OptionFunc[R]) ezQue.Interceptors[R] {
+
+	opts := Options[R]{
+		key: messageID[R],
+	}
+	for _, optFunc := range optFuncs {
+		optFunc(&opts)
+	}
+	if opts.seenAttempts <= 0 {
+		opts.seenAttempts = defaultSeenAttempts
+	}
+	if opts.seenBackoff <= 0 {
+		opts.seenBackoff = defaultSeenBackoff
+	}
+
+	d := &deduplicator[R]{
+		store: store,
+		key:   opts.key,
+		opts:  opts,
+	}
+
+	return ezQue.Interceptors[R]{
+		Dequeue: d.dequeue,
+	}
+}
+
+// messageID returns the message ID of msg, if it implements api.Identified.
+func messageID[R any](msg api.Message[R]) string {
+	if identified, ok := msg.(api.Identified); ok {
+		return identified.GetMessageID()
+	}
+	return ""
+}
+
+// deduplicator skips the messages whose key is in its store.
+type deduplicator[R any] struct {
+	store Store
+	key   func(msg api.Message[R]) string
+	opts  Options[R]
+}
+
+// dequeue dequeues until a message whose key has not been recorded is dequeued,
+// acknowledging the duplicates.
+func (d *deduplicator[R]) dequeue(ctx context.Context, next ezQue.DequeueFunc[R]) (api.DequeueMessage[R], error) {
+
+	for {
+		msg, err := next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		key := d.key(msg.Message())
+		if key == "" {
+			return msg, nil
+		}
+
+		tx := transaction(msg)
+		seen, err := d.seen(ctx, tx, key)
+		if err != nil {
+			_ = msg.NAck(ctx)
+			return nil, fmt.Errorf("failed to check for a duplicate message: %w", err)
+		}
+		if seen {
+			err = msg.Ack(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to acknowledge a duplicate message: %w", err)
+			}
+			continue
+		}
+
+		return &dedupMessage[R]{
+			DequeueMessage: msg,
+			store:          d.store,
+			tx:             tx,
+			key:            key,
+		}, nil
+	}
+}
+
+// seen checks the store for key, retrying failed checks as set by WithSeenRetry
+// until ctx is done.
+func (d *deduplicator[R]) seen(ctx context.Context, tx *sql.Tx, key string) (bool, error) {
+
+	backoff := d.opts.seenBackoff
+	for attempt := 1; ; attempt++ {
+		seen, err := d.store.Seen(ctx, tx, key)
+		if err == nil || attempt >= d.opts.seenAttempts {
+			return seen, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// transaction returns the transaction msg was dequeued in, or nil, looking through
+// the messages wrapped by other interceptors.
+func transaction[R any](msg api.DequeueMessage[R]) *sql.Tx {
+	for msg != nil {
+		if transactional, ok := msg.(api.Transactional); ok {
+			return transactional.Tx()
+		}
+		wrapper, ok := msg.(interface{ Unwrap() api.DequeueMessage[R] })
+		if !ok {
+			return nil
+		}
+		msg = wrapper.Unwrap()
+	}
+	return nil
+}
+
+// dedupMessage is a dequeued message whose key is recorded when it is acknowledged.
+type dedupMessage[R any] struct {
+	api.DequeueMessage[R]
+	store Store
+	tx    *sql.Tx
+	key   string
+}
+
+// Unwrap returns the dequeued message.
+func (m *dedupMessage[R]) Unwrap() api.DequeueMessage[R] {
+	return m.DequeueMessage
+}
+
+// Tx returns the transaction the message was dequeued in, so that it stays available
+// to handlers through the wrapper.
+func (m *dedupMessage[R]) Tx() *sql.Tx {
+	return m.tx
+}
+
+// Ack records the key of the message, within its transaction if it has one, and then
+// acknowledges it. If the key cannot be recorded the message is not acknowledged. If
+// another consumer recorded the key first, the error wraps ErrAlreadyRecorded: a
+// transaction has been rolled back, and a message without one is acknowledged as the
+// duplicate it turned out to be.
+func (m *dedupMessage[R]) Ack(ctx context.Context) error {
+
+	err := m.store.Record(ctx, m.tx, m.key)
+	if errors.Is(err, ErrAlreadyRecorded) {
+		if m.tx == nil {
+			if ackErr := m.DequeueMessage.Ack(ctx); ackErr != nil {
+				return fmt.Errorf("%w, failed to acknowledge the duplicate message: %w", err, ackErr)
+			}
+		}
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record the processed message: %w", err)
+	}
+
+	return m.DequeueMessage.Ack(ctx)
+}
@@ -0,0 +1,220 @@
+package dedup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+// message is a fake api.Message with a message ID and api.Properties.
+type message struct {
+	id   string
+	text string
+	key  string
+}
+
+func (m *message) Raw() message              { return *m }
+func (m *message) Text() string              { return m.text }
+func (m *message) SetRaw(raw message)        { *m = raw }
+func (m *message) SetText(text string)       { m.text = text }
+func (m *message) GetMessageID() string      { return m.id }
+func (m *message) GetProperty(string) string { return m.key }
+
+// delivery is a dequeued message that records whether it was acknowledged.
+type delivery struct {
+	msg   message
+	queue *fakeQueue
+}
+
+func (d *delivery) Message() api.Message[message] { return &d.msg }
+func (d *delivery) Ack(context.Context) error {
+	d.queue.acks = append(d.queue.acks, "ack "+d.msg.text)
+	return nil
+}
+func (d *delivery) NAck(context.Context) error {
+	d.queue.acks = append(d.queue.acks, "nack "+d.msg.text)
+	return nil
+}
+
+// fakeQueue is an in-memory enqueuer and dequeuer of messages.
+type fakeQueue struct {
+	msgs []message
+	acks []string
+}
+
+func (q *fakeQueue) NewMessage() api.Message[message] { return &message{} }
+func (q *fakeQueue) Enqueue(_ context.Context, msg api.Message[message]) error {
+	q.msgs = append(q.msgs, msg.Raw())
+	return nil
+}
+func (q *fakeQueue) Dequeue(context.Context) (api.DequeueMessage[message], error) {
+	if len(q.msgs) == 0 {
+		return nil, context.DeadlineExceeded
+	}
+	msg := q.msgs[0]
+	q.msgs = q.msgs[1:]
+	return &delivery{msg: msg, queue: q}, nil
+}
+func (q *fakeQueue) Disconnect(context.Context) error { return nil }
+
+// failingStore is a Store that fails to record keys.
+type failingStore struct {
+	LRU
+}
+
+func (s *failingStore) Record(context.Context, *sql.Tx, string) error {
+	return api.ErrConnectionLost
+}
+
+// connectDeduplicated connects a Queue over fake that deduplicates messages with store.
+func connectDeduplicated(t *testing.T, fake *fakeQueue, store Store, opts ...OptionFunc[message]) ezQue.Queue[message] {
+	q, err := ezQue.Connect(func(struct{}) (api.Enqueuer[message], api.Dequeuer[message], error) {
+		return fake, fake, nil
+	}, struct{}{}, ezQue.WithInterceptors(Interceptors[message](store, opts...)))
+	require.NoError(t, err)
+	return q
+}
+
+// TestInterceptors ensures that messages already acknowledged are skipped, and that
+// messages handed back with NAck are not.
+func TestInterceptors(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeQueue{msgs: []message{
+		{id: "1", text: "a"},
+		{id: "1", text: "a again"},
+		{id: "2", text: "b"},
+		{id: "2", text: "b again"},
+		{id: "", text: "c"},
+		{id: "", text: "c again"},
+	}}
+	q := connectDeduplicated(t, fake, NewLRU(10))
+
+	msg, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", msg.Message().Text())
+	require.NoError(t, msg.Ack(ctx))
+
+	msg, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "b", msg.Message().Text())
+	require.NoError(t, msg.NAck(ctx))
+
+	msg, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "b again", msg.Message().Text())
+	require.NoError(t, msg.Ack(ctx))
+
+	for _, text := range []string{"c", "c again"} {
+		msg, err = q.Dequeue(ctx)
+		require.NoError(t, err)
+		require.Equal(t, text, msg.Message().Text())
+		require.NoError(t, msg.Ack(ctx))
+	}
+
+	_, err = q.Dequeue(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, []string{"ack a", "ack a again", "nack b", "ack b again", "ack c", "ack c again"}, fake.acks)
+}
+
+// TestWithKey ensures that messages are identified with the key function.
+func TestWithKey(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeQueue{msgs: []message{
+		{id: "1", text: "a", key: "order-1"},
+		{id: "2", text: "a resent", key: "order-1"},
+	}}
+	q := connectDeduplicated(t, fake, NewLRU(10), WithKey(func(msg api.Message[message]) string {
+		return msg.(*message).GetProperty("idempotency_key")
+	}))
+
+	msg, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, msg.Ack(ctx))
+
+	_, err = q.Dequeue(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, []string{"ack a", "ack a resent"}, fake.acks)
+}
+
+// TestRecordFails ensures that a message is not acknowledged if its key cannot be
+// recorded.
+func TestRecordFails(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeQueue{msgs: []message{{id: "1", text: "a"}}}
+	q := connectDeduplicated(t, fake, &failingStore{LRU: *NewLRU(10)})
+
+	msg, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	err = msg.Ack(ctx)
+	require.True(t, errors.Is(err, api.ErrConnectionLost))
+	require.Empty(t, fake.acks)
+}
+
+// flakyStore is a Store whose Seen fails until it has been called failures times.
+type flakyStore struct {
+	LRU
+	failures int
+	calls    int
+}
+
+func (s *flakyStore) Seen(ctx context.Context, tx *sql.Tx, key string) (bool, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return false, api.ErrConnectionLost
+	}
+	return s.LRU.Seen(ctx, tx, key)
+}
+
+// TestSeenRetried ensures that failing to check for a duplicate is retried before the
+// message is handed back.
+func TestSeenRetried(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeQueue{msgs: []message{{id: "1", text: "a"}, {id: "2", text: "b"}}}
+	store := &flakyStore{LRU: *NewLRU(10), failures: 2}
+	q := connectDeduplicated(t, fake, store, WithSeenRetry[message](3, time.Millisecond))
+
+	msg, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", msg.Message().Text())
+	require.Equal(t, 3, store.calls)
+
+	// once the attempts are used up the message is handed back
+	store.calls, store.failures = 0, 3
+	_, err = q.Dequeue(ctx)
+	require.ErrorIs(t, err, api.ErrConnectionLost)
+	require.Equal(t, 3, store.calls)
+	require.Equal(t, []string{"nack b"}, fake.acks)
+}
+
+// racingStore is a Store whose keys are always recorded by another consumer first.
+type racingStore struct {
+	LRU
+}
+
+func (s *racingStore) Record(context.Context, *sql.Tx, string) error {
+	return fmt.Errorf("%w: %q", ErrAlreadyRecorded, "1")
+}
+
+// TestAlreadyRecorded ensures that a key recorded concurrently is reported with
+// ErrAlreadyRecorded, and that the duplicate is acknowledged when it was not
+// dequeued in a transaction.
+func TestAlreadyRecorded(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeQueue{msgs: []message{{id: "1", text: "a"}}}
+	q := connectDeduplicated(t, fake, &racingStore{LRU: *NewLRU(10)})
+
+	msg, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	err = msg.Ack(ctx)
+	require.ErrorIs(t, err, ErrAlreadyRecorded)
+	require.Equal(t, []string{"ack a"}, fake.acks)
+}
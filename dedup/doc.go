@@ -0,0 +1,26 @@
+// Package dedup skips messages that have already been processed, for queue systems such as
+// OracleAQ that deliver messages at least once.
+//
+// Central to the package is the Interceptors function, whose result is passed to ezQue.Connect
+// with ezQue.WithInterceptors. Every dequeued message is identified by a key, its message ID
+// (see api.Identified) or a key read from the message with WithKey. If the key is in the Store
+// the message is a duplicate: it is acknowledged and Dequeue moves on to the next message.
+// Otherwise the key is recorded in the Store when the message is acknowledged with Ack, and not
+// when it is handed back with NAck.
+//
+// Two Stores are provided. LRU remembers a fixed number of keys in memory. SQLStore keeps them in
+// a database table; for messages dequeued within a transaction (see api.Transactional), such as
+// those of OracleAQ, the key is recorded in that transaction, so it is committed together with
+// the dequeue and with any work the handler did in the transaction, giving effectively-once
+// processing. Without a transaction, as with every queue system other than OracleAQ or with LRU,
+// duplicates are only made less likely: a message whose handler fails after doing its work, or
+// that two consumers handle at the same time, can still be processed twice.
+//
+// If two consumers handle messages with the same key at the same time, the Ack of the one that
+// records the key second fails with ErrAlreadyRecorded, and its transaction is rolled back so
+// that the message is delivered again and skipped. Failing to check the Store is retried, as set
+// by WithSeenRetry, before the message is handed back with NAck.
+//
+// Note: This package relies on "ezQue", "ezQue/api" and, for SQLStore, the OracleAQ idempotency tables
+// of "ezQue/internal/oraaq".
+package dedup
@@ -0,0 +1,63 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// LRU is a Store that remembers the most recently recorded keys in memory. It is lost
+// when the process exits, and is not shared between processes.
+type LRU struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List
+	keys  map[string]*list.Element
+}
+
+// NewLRU returns an LRU that remembers up to size keys, forgetting the least recently
+// seen key when it is full.
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:  size,
+		order: list.New(),
+		keys:  make(map[string]*list.Element, size),
+	}
+}
+
+// Seen reports whether key is remembered. tx is ignored.
+func (l *LRU) Seen(_ context.Context, _ *sql.Tx, key string) (bool, error) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.keys[key]
+	if ok {
+		l.order.MoveToFront(element)
+	}
+
+	return ok, nil
+}
+
+// Record remembers key. tx is ignored.
+func (l *LRU) Record(_ context.Context, _ *sql.Tx, key string) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.keys[key]; ok {
+		l.order.MoveToFront(element)
+		return nil
+	}
+
+	l.keys[key] = l.order.PushFront(key)
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.keys, oldest.Value.(string))
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestLRU ensures that the least recently seen key is forgotten when the LRU is full.
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(2)
+
+	require.NoError(t, lru.Record(ctx, nil, "a"))
+	require.NoError(t, lru.Record(ctx, nil, "b"))
+
+	seen, err := lru.Seen(ctx, nil, "a")
+	require.NoError(t, err)
+	require.True(t, seen)
+
+	require.NoError(t, lru.Record(ctx, nil, "c"))
+
+	for key, expected := range map[string]bool{"a": true, "b": false, "c": true, "d": false} {
+		seen, err = lru.Seen(ctx, nil, key)
+		require.NoError(t, err)
+		require.Equal(t, expected, seen, key)
+	}
+}
@@ -0,0 +1,96 @@
+package dedup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/internal/oraaq"
+	"time"
+)
+
+const seenSQL = `SELECT COUNT(*) FROM %s WHERE idempotency_key = :1`
+
+// SQLStore is a Store that keeps the keys in an Oracle table, with the columns
+// idempotency_key and processed_at. Keys of messages dequeued within a transaction are
+// read and recorded in that transaction. The table has the same layout as the
+// idempotency tables of the OracleAQ Enqueuer, and is managed with the same Admin
+// methods.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns an SQLStore over the table, which may be qualified with the
+// schema. The table can be created with EnsureTable.
+func NewSQLStore(db *sql.DB, table string) (*SQLStore, error) {
+
+	table, err := oraaq.IdempotencyTableName(table)
+	if err != nil {
+		return nil, fmt.Errorf("dedup: %w", err)
+	}
+
+	return &SQLStore{
+		db:    db,
+		table: table,
+	}, nil
+}
+
+// EnsureTable creates the table, if it does not exist yet.
+func (s *SQLStore) EnsureTable(ctx context.Context) error {
+	return oraaq.NewAdmin(s.db).EnsureIdempotencyTable(ctx, s.table)
+}
+
+// Seen reports whether key is in the table, reading it in tx if it is not nil.
+func (s *SQLStore) Seen(ctx context.Context, tx *sql.Tx, key string) (bool, error) {
+
+	var count int
+	err := s.queryRow(ctx, tx, fmt.Sprintf(seenSQL, s.table), key).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to read table %s: %w", s.table, err)
+	}
+
+	return count > 0, nil
+}
+
+// Record inserts key into the table, in tx if it is not nil, so that it is committed
+// with the dequeue. If another consumer inserted key first (ORA-00001), tx is rolled
+// back and an error wrapping ErrAlreadyRecorded is returned.
+func (s *SQLStore) Record(ctx context.Context, tx *sql.Tx, key string) error {
+
+	query := fmt.Sprintf(oraaq.InsertIdempotencyKeySQL, s.table)
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, key)
+	} else {
+		_, err = s.db.ExecContext(ctx, query, key)
+	}
+	if err != nil && oraaq.IsUniqueViolation(err) {
+		if tx != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return fmt.Errorf("%w: %q, failed to rollback: %w", ErrAlreadyRecorded, key, rollbackErr)
+			}
+		}
+		return fmt.Errorf("%w: %q", ErrAlreadyRecorded, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert into table %s: %w", s.table, err)
+	}
+
+	return nil
+}
+
+// DeleteBefore deletes the keys recorded before processedBefore, returning how many
+// were deleted, so that the table does not grow forever. Keys should be kept for
+// longer than a message can be redelivered.
+func (s *SQLStore) DeleteBefore(ctx context.Context, processedBefore time.Time) (int64, error) {
+	return oraaq.NewAdmin(s.db).DeleteIdempotencyKeysBefore(ctx, s.table, processedBefore)
+}
+
+// queryRow runs the query in tx if it is not nil, and otherwise in the db.
+func (s *SQLStore) queryRow(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) *sql.Row {
+	if tx != nil {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return s.db.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,113 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewSQLStore ensures that table names that could inject SQL are rejected.
+func TestNewSQLStore(t *testing.T) {
+	for _, table := range []string{"processed", "aq.processed_msgs"} {
+		_, err := NewSQLStore(nil, table)
+		require.NoError(t, err, table)
+	}
+	for _, table := range []string{"", "1processed", "processed; DROP TABLE x", "a.b.c"} {
+		_, err := NewSQLStore(nil, table)
+		require.Error(t, err, table)
+	}
+}
+
+// TestSQLStore ensures that keys are read and recorded in the transaction of the
+// message, and in the db without one.
+func TestSQLStore(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store, err := NewSQLStore(db, "processed")
+	require.NoError(t, err)
+
+	seenQuery := regexp.QuoteMeta("SELECT COUNT(*) FROM PROCESSED WHERE idempotency_key = :1")
+	recordQuery := regexp.QuoteMeta("INSERT INTO PROCESSED (idempotency_key) VALUES (:1)")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(seenQuery).WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(recordQuery).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(seenQuery).WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(recordQuery).WithArgs("2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM PROCESSED WHERE processed_at < :1")).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	seen, err := store.Seen(ctx, tx, "1")
+	require.NoError(t, err)
+	require.False(t, seen)
+	require.NoError(t, store.Record(ctx, tx, "1"))
+	require.NoError(t, tx.Commit())
+
+	seen, err = store.Seen(ctx, nil, "1")
+	require.NoError(t, err)
+	require.True(t, seen)
+	require.NoError(t, store.Record(ctx, nil, "2"))
+
+	deleted, err := store.DeleteBefore(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSQLStore_EnsureTable ensures that a table which already exists is not an error.
+func TestSQLStore_EnsureTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store, err := NewSQLStore(db, "aq.processed")
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE AQ.PROCESSED")).
+		WillReturnError(errors.New("ORA-00955: name is already used by an existing object"))
+
+	require.NoError(t, store.EnsureTable(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSQLStore_AlreadyRecorded ensures that a key inserted by another consumer first
+// rolls back the transaction and is reported with ErrAlreadyRecorded, while other
+// errors are not.
+func TestSQLStore_AlreadyRecorded(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store, err := NewSQLStore(db, "processed")
+	require.NoError(t, err)
+	recordQuery := regexp.QuoteMeta("INSERT INTO PROCESSED (idempotency_key) VALUES (:1)")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(recordQuery).WithArgs("1").
+		WillReturnError(errors.New("ORA-00001: unique constraint (AQ.SYS_C008) violated"))
+	mock.ExpectRollback()
+	mock.ExpectExec(recordQuery).WithArgs("2").
+		WillReturnError(errors.New("ORA-04088: error during execution of trigger 'AQ.AUDIT'\nORA-00001: unique constraint (AQ.SYS_C009) violated"))
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, store.Record(ctx, tx, "1"), ErrAlreadyRecorded)
+
+	err = store.Record(ctx, nil, "2")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrAlreadyRecorded)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,44 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+// DequeueWhere dequeues the first message in q matching sel, blocking until one is
+// available. It returns api.ErrNotSupported if q does not support api.SelectiveDequeuer.
+func DequeueWhere[R any](ctx context.Context, q Queue[R], sel api.Selector) (api.DequeueMessage[R], error) {
+
+	dequeuer, ok := q.(api.SelectiveDequeuer[R])
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	return dequeuer.DequeueWhere(ctx, sel)
+}
+
+// DequeueWhere delegates to the dequeuer, through the dequeue interceptors, if it
+// supports api.SelectiveDequeuer, and otherwise returns api.ErrNotSupported.
+func (q *queue[R]) DequeueWhere(ctx context.Context, sel api.Selector) (api.DequeueMessage[R], error) {
+
+	dequeuer, ok := q.dequeuer.(api.SelectiveDequeuer[R])
+	if !ok {
+		return nil, api.ErrNotSupported
+	}
+
+	return q.interceptDequeue(ctx, func(ctx context.Context) (api.DequeueMessage[R], error) {
+		return dequeuer.DequeueWhere(ctx, sel)
+	})
+}
+
+// selective reports whether q can dequeue selectively, without calling it.
+func selective[R any](q Queue[R]) bool {
+
+	if inner, ok := q.(*queue[R]); ok {
+		_, ok = inner.dequeuer.(api.SelectiveDequeuer[R])
+		return ok
+	}
+
+	_, ok := q.(api.SelectiveDequeuer[R])
+	return ok
+}
@@ -0,0 +1,27 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"testing"
+)
+
+// TestDequeueWhereNotSupported ensures that DequeueWhere reports queue systems without
+// selective dequeue support.
+func TestDequeueWhereNotSupported(t *testing.T) {
+	fq := &fakeQueue{msgs: []string{"a"}}
+	q, err := Connect(func(struct{}) (api.Enqueuer[string], api.Dequeuer[string], error) {
+		return fq, fq, nil
+	}, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = DequeueWhere(context.Background(), q, api.Selector{CorrelationID: "a"})
+	if err != api.ErrNotSupported {
+		t.Fatalf("expected api.ErrNotSupported, got %v", err)
+	}
+	if len(fq.msgs) != 1 {
+		t.Fatal("expected the message to be left in the queue")
+	}
+}
@@ -10,11 +10,14 @@
 // A queueConnector is provided as a function type for connecting to a specific queue system, configuring the connection options,
 // and initializing Enqueuer and Dequeuer interfaces.
 //
+// EnqueueBatch and DequeueBatch send or receive several messages at once on queue systems that support batching,
+// and fall back to one message at a time on others.
+//
 // The Connect function initializes a connection to a queue, taking as parameters a queueConnector for getting
 // system-specific Enqueuer and Dequeuer and an options parameter for Enqueuer/Dequeuer configuration.
 //
-// Note: The current release of ezQue only supports OracleAQ but the design intends to accommodate additional queue systems
-// such as ActiveMQ/Artemis and Apache Kafka in the future.
+// Note: The current release of ezQue supports OracleAQ, Apache Kafka, AMQP 0-9-1 (RabbitMQ), NATS JetStream, Redis
+// Streams and Amazon SQS, and the design intends to accommodate additional queue systems such as ActiveMQ/Artemis in the future.
 //
 // By making queuing operations consistent and system-agnostic, ezQue makes it easier for developers to implement
 // various operations on any supported messaging system.
@@ -0,0 +1,37 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	"testing"
+)
+
+// fakeHealthChecker is a fakeQueue that supports api.HealthChecker.
+type fakeHealthChecker struct {
+	fakeQueue
+	err error
+}
+
+func (q *fakeHealthChecker) Health(context.Context) error {
+	return q.err
+}
+
+// TestHealth ensures that Health reports the error of an api.HealthChecker.
+func TestHealth(t *testing.T) {
+	q := connectFake[string](t, &fakeHealthChecker{err: api.ErrConnectionLost})
+
+	err := q.Health(context.Background())
+	if !errors.Is(err, api.ErrConnectionLost) {
+		t.Fatalf("expected api.ErrConnectionLost, got %v", err)
+	}
+}
+
+// TestHealthNotSupported ensures that queue systems without an api.HealthChecker are healthy.
+func TestHealthNotSupported(t *testing.T) {
+	q := connectFake[string](t, &fakeQueue{})
+
+	if err := q.Health(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
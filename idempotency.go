@@ -0,0 +1,24 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+// EnqueueIdempotent enqueues msg with an idempotency key, so that enqueueing it again
+// with the same key, for example when retrying after a timeout left it unclear whether
+// the first attempt succeeded, does not enqueue a duplicate. It returns
+// api.ErrNotSupported if msg does not support api.Idempotent.
+//
+// OracleAQ enforces the key with a table set with oraaq.WithIdempotencyTable, SQS with
+// the deduplication ID of FIFO queues and JetStream with the Nats-Msg-Id header.
+func EnqueueIdempotent[R any](ctx context.Context, q Queue[R], msg api.Message[R], key string) error {
+
+	idempotent, ok := msg.(api.Idempotent)
+	if !ok {
+		return api.ErrNotSupported
+	}
+	idempotent.SetIdempotencyKey(key)
+
+	return q.Enqueue(ctx, msg)
+}
@@ -0,0 +1,71 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	"reflect"
+	"testing"
+)
+
+// keyedMessage is a fakeMessage with an idempotency key.
+type keyedMessage struct {
+	fakeMessage
+	key string
+}
+
+func (m *keyedMessage) GetIdempotencyKey() string    { return m.key }
+func (m *keyedMessage) SetIdempotencyKey(key string) { m.key = key }
+
+// keyedQueue is a fakeQueue that enqueues a message at most once per idempotency key.
+type keyedQueue struct {
+	fakeQueue
+	keys map[string]bool
+}
+
+func (q *keyedQueue) Enqueue(ctx context.Context, msg api.Message[string]) error {
+	key := msg.(api.Idempotent).GetIdempotencyKey()
+	if q.keys[key] {
+		return nil
+	}
+	q.keys[key] = true
+	return q.fakeQueue.Enqueue(ctx, msg)
+}
+
+// TestEnqueueIdempotent ensures that the idempotency key is set on the message, so
+// that enqueueing it again does not enqueue a duplicate.
+func TestEnqueueIdempotent(t *testing.T) {
+	ctx := context.Background()
+	fake := &keyedQueue{keys: make(map[string]bool)}
+	q := connectFake[string](t, fake)
+
+	for _, text := range []string{"a", "a retried"} {
+		err := EnqueueIdempotent(ctx, q, &keyedMessage{fakeMessage: fakeMessage{text: text}}, "order-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := EnqueueIdempotent(ctx, q, &keyedMessage{fakeMessage: fakeMessage{text: "b"}}, "order-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(fake.msgs, []string{"a", "b"}) {
+		t.Fatalf("expected one message per key, got %v", fake.msgs)
+	}
+}
+
+// TestEnqueueIdempotentNotSupported ensures that messages without idempotency keys
+// are not enqueued.
+func TestEnqueueIdempotentNotSupported(t *testing.T) {
+	fake := &fakeQueue{}
+	q := connectFake[string](t, fake)
+
+	err := EnqueueIdempotent(context.Background(), q, &fakeMessage{text: "a"}, "order-1")
+	if !errors.Is(err, api.ErrNotSupported) {
+		t.Fatalf("expected api.ErrNotSupported, got %v", err)
+	}
+	if len(fake.msgs) != 0 {
+		t.Fatalf("expected nothing to be enqueued, got %v", fake.msgs)
+	}
+}
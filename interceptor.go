@@ -0,0 +1,163 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+// EnqueueFunc enqueues a message. It is the next step passed to an EnqueueInterceptor.
+type EnqueueFunc[R any] func(ctx context.Context, msg api.Message[R]) error
+
+// EnqueueInterceptor is called around every enqueue. It may inspect or change the
+// message, and continues the enqueue by calling next, or fails it by returning an
+// error without doing so.
+type EnqueueInterceptor[R any] func(ctx context.Context, msg api.Message[R], next EnqueueFunc[R]) error
+
+// DequeueFunc dequeues a message. It is the next step passed to a DequeueInterceptor.
+type DequeueFunc[R any] func(ctx context.Context) (api.DequeueMessage[R], error)
+
+// DequeueInterceptor is called around every dequeue. It continues the dequeue by
+// calling next, and may inspect or change the message it returns.
+type DequeueInterceptor[R any] func(ctx context.Context, next DequeueFunc[R]) (api.DequeueMessage[R], error)
+
+// AckFunc acknowledges, or negatively acknowledges, a dequeued message. It is the
+// next step passed to an AckInterceptor.
+type AckFunc[R any] func(ctx context.Context, msg api.DequeueMessage[R]) error
+
+// AckInterceptor is called around every Ack and NAck of a dequeued message; ack is
+// true for Ack and false for NAck. It continues by calling next.
+type AckInterceptor[R any] func(ctx context.Context, msg api.DequeueMessage[R], ack bool, next AckFunc[R]) error
+
+// ConnectOption configures the Queue returned by Connect.
+type ConnectOption[R any] func(*queue[R])
+
+// WithEnqueueInterceptors adds interceptors that are called around every enqueue.
+// The first interceptor is the outermost, and is called first.
+func WithEnqueueInterceptors[R any](interceptors ...EnqueueInterceptor[R]) ConnectOption[R] {
+	return func(q *queue[R]) {
+		q.enqueueInterceptors = append(q.enqueueInterceptors, interceptors...)
+	}
+}
+
+// WithDequeueInterceptors adds interceptors that are called around every dequeue.
+// The first interceptor is the outermost, and is called first.
+func WithDequeueInterceptors[R any](interceptors ...DequeueInterceptor[R]) ConnectOption[R] {
+	return func(q *queue[R]) {
+		q.dequeueInterceptors = append(q.dequeueInterceptors, interceptors...)
+	}
+}
+
+// WithAckInterceptors adds interceptors that are called around every Ack and NAck of
+// a dequeued message. The first interceptor is the outermost, and is called first.
+func WithAckInterceptors[R any](interceptors ...AckInterceptor[R]) ConnectOption[R] {
+	return func(q *queue[R]) {
+		q.ackInterceptors = append(q.ackInterceptors, interceptors...)
+	}
+}
+
+// Interceptors groups the interceptors of a concern, such as tracing or metrics, that
+// spans enqueue, dequeue and acknowledgement. Nil interceptors are skipped.
+type Interceptors[R any] struct {
+	Enqueue EnqueueInterceptor[R]
+	Dequeue DequeueInterceptor[R]
+	Ack     AckInterceptor[R]
+}
+
+// WithInterceptors adds the interceptors of a concern, after those already added.
+func WithInterceptors[R any](interceptors Interceptors[R]) ConnectOption[R] {
+	return func(q *queue[R]) {
+		if interceptors.Enqueue != nil {
+			q.enqueueInterceptors = append(q.enqueueInterceptors, interceptors.Enqueue)
+		}
+		if interceptors.Dequeue != nil {
+			q.dequeueInterceptors = append(q.dequeueInterceptors, interceptors.Dequeue)
+		}
+		if interceptors.Ack != nil {
+			q.ackInterceptors = append(q.ackInterceptors, interceptors.Ack)
+		}
+	}
+}
+
+// interceptEnqueue enqueues msg through the enqueue interceptors, ending with enqueue.
+func (q *queue[R]) interceptEnqueue(ctx context.Context, msg api.Message[R], enqueue EnqueueFunc[R]) error {
+
+	next := enqueue
+	for i := len(q.enqueueInterceptors) - 1; i >= 0; i-- {
+		interceptor, inner := q.enqueueInterceptors[i], next
+		next = func(ctx context.Context, msg api.Message[R]) error {
+			return interceptor(ctx, msg, inner)
+		}
+	}
+
+	return next(ctx, msg)
+}
+
+// interceptDequeue dequeues through the dequeue interceptors, ending with dequeue,
+// and wraps the message so that its Ack and NAck pass through the ack interceptors.
+func (q *queue[R]) interceptDequeue(ctx context.Context, dequeue DequeueFunc[R]) (api.DequeueMessage[R], error) {
+
+	next := dequeue
+	for i := len(q.dequeueInterceptors) - 1; i >= 0; i-- {
+		interceptor, inner := q.dequeueInterceptors[i], next
+		next = func(ctx context.Context) (api.DequeueMessage[R], error) {
+			return interceptor(ctx, inner)
+		}
+	}
+
+	msg, err := next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.interceptAck(msg), nil
+}
+
+// interceptAck wraps msg so that its Ack and NAck pass through the ack interceptors.
+func (q *queue[R]) interceptAck(msg api.DequeueMessage[R]) api.DequeueMessage[R] {
+
+	if len(q.ackInterceptors) == 0 || msg == nil {
+		return msg
+	}
+
+	return &interceptedMessage[R]{
+		DequeueMessage: msg,
+		interceptors:   q.ackInterceptors,
+	}
+}
+
+// interceptedMessage is a dequeued message whose Ack and NAck pass through
+// AckInterceptors.
+type interceptedMessage[R any] struct {
+	api.DequeueMessage[R]
+	interceptors []AckInterceptor[R]
+}
+
+// Unwrap returns the dequeued message the interceptors were added around.
+func (m *interceptedMessage[R]) Unwrap() api.DequeueMessage[R] {
+	return m.DequeueMessage
+}
+
+func (m *interceptedMessage[R]) Ack(ctx context.Context) error {
+	return m.intercept(ctx, true, func(ctx context.Context, msg api.DequeueMessage[R]) error {
+		return msg.Ack(ctx)
+	})
+}
+
+func (m *interceptedMessage[R]) NAck(ctx context.Context) error {
+	return m.intercept(ctx, false, func(ctx context.Context, msg api.DequeueMessage[R]) error {
+		return msg.NAck(ctx)
+	})
+}
+
+// intercept runs ack through the interceptors on the wrapped message.
+func (m *interceptedMessage[R]) intercept(ctx context.Context, ack bool, next AckFunc[R]) error {
+
+	for i := len(m.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := m.interceptors[i], next
+		next = func(ctx context.Context, msg api.DequeueMessage[R]) error {
+			return interceptor(ctx, msg, ack, inner)
+		}
+	}
+
+	return next(ctx, m.DequeueMessage)
+}
@@ -0,0 +1,141 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ackQueue is a fakeQueue whose messages record whether they were acknowledged.
+type ackQueue struct {
+	fakeQueue
+	acks []string
+}
+
+type ackMessage struct {
+	fakeMessage
+	queue *ackQueue
+}
+
+func (m *ackMessage) Message() api.Message[string] { return &m.fakeMessage }
+func (m *ackMessage) Ack(context.Context) error {
+	m.queue.acks = append(m.queue.acks, "ack "+m.text)
+	return nil
+}
+func (m *ackMessage) NAck(context.Context) error {
+	m.queue.acks = append(m.queue.acks, "nack "+m.text)
+	return nil
+}
+
+func (q *ackQueue) Dequeue(ctx context.Context) (api.DequeueMessage[string], error) {
+	msg, err := q.fakeQueue.Dequeue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ackMessage{fakeMessage: fakeMessage{text: msg.Message().Text()}, queue: q}, nil
+}
+
+// connectIntercepted connects a Queue over fake with the given ConnectOptions.
+func connectIntercepted(t *testing.T, fake *ackQueue, opts ...ConnectOption[string]) Queue[string] {
+	q, err := Connect(func(struct{}) (api.Enqueuer[string], api.Dequeuer[string], error) {
+		return fake, fake, nil
+	}, struct{}{}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+// TestEnqueueInterceptors ensures that enqueue interceptors are called in order and
+// can change the message.
+func TestEnqueueInterceptors(t *testing.T) {
+	var calls []string
+	fake := &ackQueue{}
+	q := connectIntercepted(t, fake, WithEnqueueInterceptors(
+		func(ctx context.Context, msg api.Message[string], next EnqueueFunc[string]) error {
+			calls = append(calls, "first")
+			return next(ctx, msg)
+		},
+		func(ctx context.Context, msg api.Message[string], next EnqueueFunc[string]) error {
+			calls = append(calls, "second")
+			msg.SetText(strings.ToUpper(msg.Text()))
+			return next(ctx, msg)
+		},
+	))
+
+	err := q.Enqueue(context.Background(), &fakeMessage{text: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, []string{"first", "second"}) {
+		t.Fatalf("unexpected interceptor calls %v", calls)
+	}
+	if !reflect.DeepEqual(fake.msgs, []string{"A"}) {
+		t.Fatalf("expected the transformed message, got %v", fake.msgs)
+	}
+}
+
+// TestEnqueueInterceptorRejects ensures that an interceptor can fail an enqueue.
+func TestEnqueueInterceptorRejects(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	fake := &ackQueue{}
+	q := connectIntercepted(t, fake, WithEnqueueInterceptors(
+		func(ctx context.Context, msg api.Message[string], next EnqueueFunc[string]) error {
+			return errInvalid
+		},
+	))
+
+	err := EnqueueBatch(context.Background(), q, []api.Message[string]{&fakeMessage{text: "a"}})
+	if !errors.Is(err, errInvalid) {
+		t.Fatalf("expected the interceptor error, got %v", err)
+	}
+	if len(fake.msgs) != 0 {
+		t.Fatalf("expected no messages enqueued, got %v", fake.msgs)
+	}
+}
+
+// TestDequeueAndAckInterceptors ensures that dequeue and ack interceptors are called
+// around Dequeue, Ack and NAck.
+func TestDequeueAndAckInterceptors(t *testing.T) {
+	var calls []string
+	fake := &ackQueue{fakeQueue: fakeQueue{msgs: []string{"a", "b"}}}
+	q := connectIntercepted(t, fake,
+		WithDequeueInterceptors(func(ctx context.Context, next DequeueFunc[string]) (api.DequeueMessage[string], error) {
+			msg, err := next(ctx)
+			if err == nil {
+				calls = append(calls, "dequeue "+msg.Message().Text())
+			}
+			return msg, err
+		}),
+		WithAckInterceptors(func(ctx context.Context, msg api.DequeueMessage[string], ack bool, next AckFunc[string]) error {
+			calls = append(calls, "intercept "+msg.Message().Text())
+			return next(ctx, msg)
+		}),
+	)
+
+	ctx := context.Background()
+	first, err := q.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Ack(ctx); err != nil {
+		t.Fatal(err)
+	}
+	second, err := q.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := second.NAck(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(calls, []string{"dequeue a", "intercept a", "dequeue b", "intercept b"}) {
+		t.Fatalf("unexpected interceptor calls %v", calls)
+	}
+	if !reflect.DeepEqual(fake.acks, []string{"ack a", "nack b"}) {
+		t.Fatalf("unexpected acknowledgements %v", fake.acks)
+	}
+}
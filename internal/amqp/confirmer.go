@@ -0,0 +1,99 @@
+package amqp
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+	"sync"
+)
+
+// confirmer hands the publisher confirms of a channel to the publishes waiting for
+// them. It reads the confirms as soon as the broker sends them, so that a confirm
+// nobody waits for any more, because its Enqueue returned when its context was done,
+// never blocks the connection, and its heartbeats, behind a full channel.
+type confirmer struct {
+	mu sync.Mutex
+
+	// published is the delivery tag of the last message published on the
+	// channel, which the broker numbers from 1 in confirm mode.
+	published uint64
+
+	// waiters receive the confirms of the deliveries whose publishes wait for them.
+	waiters map[uint64]chan amqp.Confirmation
+
+	// closed is set once the channel has closed, after which no confirms arrive.
+	closed bool
+}
+
+// newConfirmer returns a confirmer reading confirms until the channel closes them.
+func newConfirmer(confirms <-chan amqp.Confirmation) *confirmer {
+	c := &confirmer{
+		waiters: make(map[uint64]chan amqp.Confirmation),
+	}
+	go c.run(confirms)
+	return c
+}
+
+// run hands every confirm to its waiter, dropping those nobody waits for, and closes
+// the waiters once the channel has closed.
+func (c *confirmer) run(confirms <-chan amqp.Confirmation) {
+
+	for confirm := range confirms {
+		c.mu.Lock()
+		waiter, ok := c.waiters[confirm.DeliveryTag]
+		delete(c.waiters, confirm.DeliveryTag)
+		c.mu.Unlock()
+
+		if ok {
+			waiter <- confirm
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for tag, waiter := range c.waiters {
+		close(waiter)
+		delete(c.waiters, tag)
+	}
+}
+
+// expect registers a waiter for the confirm of the next publish, which must be made
+// before the next call. The waiter is closed if the channel closes before the confirm
+// arrives.
+func (c *confirmer) expect() (uint64, <-chan amqp.Confirmation) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	waiter := make(chan amqp.Confirmation, 1)
+	if c.closed {
+		close(waiter)
+		return 0, waiter
+	}
+
+	c.published++
+	c.waiters[c.published] = waiter
+	return c.published, waiter
+}
+
+// unpublish withdraws the waiter of a publish that failed, whose delivery tag the
+// channel does not use.
+func (c *confirmer) unpublish(tag uint64) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.waiters[tag]; ok && tag == c.published {
+		delete(c.waiters, tag)
+		c.published--
+	}
+}
+
+// forget withdraws the waiter of a publish that no longer waits for its confirm,
+// which is then dropped when it arrives.
+func (c *confirmer) forget(tag uint64) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.waiters, tag)
+}
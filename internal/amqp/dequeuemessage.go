@@ -0,0 +1,27 @@
+package amqp
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+type DequeueMessage struct {
+	message Message
+	channel *channel
+}
+
+func (d *DequeueMessage) Message() api.Message[Message] {
+	return &d.message
+}
+
+// Ack sends basic.ack for the delivery. Delivery tags are scoped to the channel
+// they were delivered on, so Ack fails if that channel has since been recovered;
+// the broker redelivers the message in that case.
+func (d *DequeueMessage) Ack(_ context.Context) error {
+	return d.channel.Ack(d.message.DeliveryTag, false)
+}
+
+// NAck sends basic.nack with requeue set, returning the message to the queue.
+func (d *DequeueMessage) NAck(_ context.Context) error {
+	return d.channel.Nack(d.message.DeliveryTag, false, true)
+}
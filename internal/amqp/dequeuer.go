@@ -0,0 +1,100 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"time"
+)
+
+func NewDequeuer(dial Dialer, queueName string, prefetch int, recoveryInterval time.Duration) *Dequeuer {
+	d := &Dequeuer{
+		queueName: queueName,
+		prefetch:  prefetch,
+	}
+	d.session = newSession(dial, recoveryInterval, d.setup)
+	return d
+}
+
+// Dequeuer consumes messages from an AMQP queue with manual acknowledgements.
+type Dequeuer struct {
+	session *session
+
+	// The AMQP queue that the Dequeuer is bound to.
+	queueName string
+
+	// prefetch is the maximum number of unacknowledged messages the broker
+	// delivers to this consumer. Zero leaves it unlimited.
+	prefetch int
+}
+
+// setup applies the prefetch limit to a newly opened channel and starts consuming.
+func (d *Dequeuer) setup(ch *channel) error {
+	err := ch.Qos(d.prefetch, 0, false)
+	if err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+	deliveries, err := ch.Consume(d.queueName, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume from queue: %w", err)
+	}
+	ch.deliveries = deliveries
+	return nil
+}
+
+// Connect opens the connection and starts consuming ahead of the first Dequeue.
+func (d *Dequeuer) Connect(ctx context.Context) error {
+	_, err := d.session.channel(ctx)
+	return err
+}
+
+// Dequeue waits for the next delivery on the bound queue. It blocks until a
+// message is available or until the context is cancelled, in which case the
+// context's error is returned. If the channel is lost while waiting, it is
+// recovered and consumption resumes.
+func (d *Dequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	for {
+		ch, err := d.session.channel(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case delivery, ok := <-ch.deliveries:
+			if !ok {
+				// The channel was closed or the consumer cancelled by the
+				// broker; recover the channel on the next iteration.
+				_ = ch.Close()
+				continue
+			}
+
+			message := Message{
+				Content:       string(delivery.Body),
+				ContentType:   delivery.ContentType,
+				CorrelationID: delivery.CorrelationId,
+				ReplyTo:       delivery.ReplyTo,
+				MessageID:     delivery.MessageId,
+				Priority:      delivery.Priority,
+				Timestamp:     delivery.Timestamp,
+				Headers:       delivery.Headers,
+				DeliveryTag:   delivery.DeliveryTag,
+				Redelivered:   delivery.Redelivered,
+			}
+
+			deqMsg := &DequeueMessage{
+				message: message,
+				channel: ch,
+			}
+
+			return deqMsg, nil
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (d *Dequeuer) Disconnect(_ context.Context) error {
+	return d.session.close()
+}
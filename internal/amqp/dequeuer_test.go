@@ -0,0 +1,94 @@
+package amqp
+
+import (
+	"context"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+func TestDequeuerTestSuite(t *testing.T) {
+	suite.Run(t, new(DequeuerTestSuite))
+}
+
+type DequeuerTestSuite struct {
+	suite.Suite
+	broker   *fakeBroker
+	dequeuer *Dequeuer
+}
+
+func (suite *DequeuerTestSuite) SetupTest() {
+	suite.broker = &fakeBroker{}
+	suite.dequeuer = NewDequeuer(suite.broker.dial, "text_msg_queue", 5, time.Millisecond)
+	suite.Require().NoError(suite.dequeuer.Connect(context.Background()))
+}
+
+func (suite *DequeuerTestSuite) TestPrefetch() {
+	suite.Equal(5, suite.broker.channel().prefetch)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueEmptyQueue() {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Nil(deqMsg, "DequeueMessage should be nil when there is an error")
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndAck() {
+	suite.broker.channel().deliveries <- amqp.Delivery{DeliveryTag: 7, Body: []byte("test message"), CorrelationId: "abc"}
+
+	deqMsg, err := suite.dequeuer.Dequeue(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.Equal("abc", deqMsg.Message().Raw().CorrelationID)
+
+	suite.NoError(deqMsg.Ack(context.Background()))
+	suite.Equal([]uint64{7}, suite.broker.acked)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndNAck() {
+	suite.broker.channel().deliveries <- amqp.Delivery{DeliveryTag: 7, Body: []byte("test message")}
+
+	deqMsg, err := suite.dequeuer.Dequeue(context.Background())
+	suite.Require().NoError(err)
+
+	suite.NoError(deqMsg.NAck(context.Background()))
+	suite.Equal([]uint64{7}, suite.broker.nacked)
+}
+
+func (suite *DequeuerTestSuite) TestDequeue_RecoversChannel() {
+	first := suite.broker.channel()
+	suite.Require().NoError(first.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Deliver on the recovered channel once it has been opened
+	go func() {
+		for {
+			if ch := suite.broker.channel(); ch != first {
+				ch.deliveries <- amqp.Delivery{DeliveryTag: 1, Body: []byte("after recovery")}
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("after recovery", deqMsg.Message().Text())
+}
+
+func (suite *DequeuerTestSuite) TestAck_AfterChannelLost() {
+	suite.broker.channel().deliveries <- amqp.Delivery{DeliveryTag: 7, Body: []byte("test message")}
+
+	deqMsg, err := suite.dequeuer.Dequeue(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.broker.channel().Close())
+
+	suite.Error(deqMsg.Ack(context.Background()), "Ack on a lost channel should fail")
+}
@@ -0,0 +1,108 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"sync"
+	"time"
+)
+
+func NewEnqueuer(dial Dialer, exchange, routingKey string, recoveryInterval time.Duration) *Enqueuer {
+	e := &Enqueuer{
+		exchange:   exchange,
+		routingKey: routingKey,
+	}
+	e.session = newSession(dial, recoveryInterval, e.setup)
+	return e
+}
+
+// Enqueuer publishes messages to an AMQP exchange with publisher confirms enabled.
+type Enqueuer struct {
+
+	// mu serialises publishes, so that every publish can be matched
+	// with the confirmation the broker returns for it.
+	mu sync.Mutex
+
+	session *session
+
+	// The exchange and routing key messages are published with. Publishing
+	// to the default exchange ("") with a queue name as routing key
+	// delivers straight to that queue.
+	exchange   string
+	routingKey string
+}
+
+// setup puts a newly opened channel into confirm mode.
+func (e *Enqueuer) setup(ch *channel) error {
+	err := ch.Confirm(false)
+	if err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	ch.confirms = newConfirmer(ch.NotifyPublish(make(chan amqp.Confirmation, 1)))
+	return nil
+}
+
+// Connect opens the connection and channel ahead of the first Enqueue.
+func (e *Enqueuer) Connect(ctx context.Context) error {
+	_, err := e.session.channel(ctx)
+	return err
+}
+
+// NewMessage returns a new, empty instance of `Message` that implements the `api.Message` interface.
+func (e *Enqueuer) NewMessage() api.Message[Message] {
+	return &Message{}
+}
+
+// Enqueue publishes a message and waits until the broker has confirmed it. A message
+// the broker nacks, or one whose channel closes before it is confirmed, is returned
+// as an error. A lost channel is recovered before publishing. If ctx is done before
+// the confirm arrives, the confirm is dropped when it does.
+func (e *Enqueuer) Enqueue(ctx context.Context, msg api.Message[Message]) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ch, err := e.session.channel(ctx)
+	if err != nil {
+		return err
+	}
+
+	raw := msg.Raw()
+	tag, confirmed := ch.confirms.expect()
+	err = ch.PublishWithContext(ctx, e.exchange, e.routingKey, false, false, amqp.Publishing{
+		Headers:       raw.Headers,
+		ContentType:   raw.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		Priority:      raw.Priority,
+		CorrelationId: raw.CorrelationID,
+		ReplyTo:       raw.ReplyTo,
+		MessageId:     raw.MessageID,
+		Timestamp:     raw.Timestamp,
+		Body:          []byte(raw.Content),
+	})
+	if err != nil {
+		ch.confirms.unpublish(tag)
+		return fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	// Wait for the publisher confirm of this delivery
+	select {
+	case confirm, ok := <-confirmed:
+		if !ok {
+			return fmt.Errorf("failed to enqueue message: channel closed before the broker confirmed it")
+		}
+		if !confirm.Ack {
+			return fmt.Errorf("failed to enqueue message: broker nacked delivery %d", confirm.DeliveryTag)
+		}
+	case <-ctx.Done():
+		ch.confirms.forget(tag)
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (e *Enqueuer) Disconnect(_ context.Context) error {
+	return e.session.close()
+}
@@ -0,0 +1,117 @@
+package amqp
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type EnqueuerTestSuite struct {
+	suite.Suite
+	broker   *fakeBroker
+	enqueuer *Enqueuer
+}
+
+func TestEnqueuerTestSuite(t *testing.T) {
+	suite.Run(t, new(EnqueuerTestSuite))
+}
+
+func (suite *EnqueuerTestSuite) SetupTest() {
+	suite.broker = &fakeBroker{}
+	suite.enqueuer = NewEnqueuer(suite.broker.dial, "", "text_msg_queue", time.Millisecond)
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue() {
+	message := &Message{
+		Content:       "test message",
+		CorrelationID: "abc",
+		Headers:       map[string]interface{}{"h": "v"},
+	}
+
+	err := suite.enqueuer.Enqueue(context.Background(), message)
+	suite.NoError(err, "Failed to enqueue message")
+
+	suite.Require().Len(suite.broker.published, 1)
+	published := suite.broker.published[0]
+	suite.Equal("test message", string(published.Body))
+	suite.Equal("abc", published.CorrelationId)
+	suite.Equal(map[string]interface{}{"h": "v"}, map[string]interface{}(published.Headers))
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_Nacked() {
+	suite.broker.nackAll = true
+
+	err := suite.enqueuer.Enqueue(context.Background(), &Message{Content: "test message"})
+	suite.Error(err, "Expected an error when the broker nacks the publish")
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_LateConfirm() {
+	suite.Require().NoError(suite.enqueuer.Connect(context.Background()))
+	suite.broker.holdConfirms = true
+	suite.broker.nackAll = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := suite.enqueuer.Enqueue(ctx, &Message{Content: "cancelled message"})
+	suite.ErrorIs(err, context.DeadlineExceeded)
+
+	// The nack of the cancelled delivery arrives late, and must not be taken as
+	// the confirm of the next one
+	suite.broker.holdConfirms = false
+	suite.broker.nackAll = false
+	suite.broker.channel().releaseConfirms()
+
+	err = suite.enqueuer.Enqueue(context.Background(), &Message{Content: "test message"})
+	suite.NoError(err, "Enqueue should wait for the confirm of its own delivery")
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_UnreadConfirmsDoNotBlock() {
+	suite.Require().NoError(suite.enqueuer.Connect(context.Background()))
+	suite.broker.holdConfirms = true
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		err := suite.enqueuer.Enqueue(ctx, &Message{Content: "cancelled message"})
+		cancel()
+		suite.ErrorIs(err, context.DeadlineExceeded)
+	}
+
+	// The broker delivers the confirms nobody waits for without blocking
+	released := make(chan struct{})
+	go func() {
+		suite.broker.channel().releaseConfirms()
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		suite.FailNow("Delivering unread confirms blocked")
+	}
+
+	suite.broker.holdConfirms = false
+	err := suite.enqueuer.Enqueue(context.Background(), &Message{Content: "test message"})
+	suite.NoError(err)
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_RecoversChannel() {
+	suite.Require().NoError(suite.enqueuer.Connect(context.Background()))
+	suite.Require().NoError(suite.broker.channel().Close())
+
+	err := suite.enqueuer.Enqueue(context.Background(), &Message{Content: "test message"})
+	suite.NoError(err, "Enqueue should recover the closed channel")
+	suite.Len(suite.broker.channels, 2)
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_disconnect() {
+	suite.broker.dialErr = context.Canceled
+
+	// Create a context with cancel
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := suite.enqueuer.Enqueue(ctx, &Message{Content: "test message"})
+
+	// Expect error due to context being cancelled
+	suite.Error(err, "Expected an error when attempting to enqueue with a cancelled context")
+}
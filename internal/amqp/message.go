@@ -0,0 +1,94 @@
+package amqp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Message is an AMQP message as seen by ezQue. Content is published as the
+// message body; the remaining fields map onto the AMQP basic properties.
+// DeliveryTag and Redelivered are populated on Dequeue and are ignored on Enqueue.
+type Message struct {
+	Content       string
+	ContentType   string
+	CorrelationID string
+	ReplyTo       string
+	MessageID     string
+	Priority      uint8
+	Timestamp     time.Time
+	Headers       map[string]interface{}
+	DeliveryTag   uint64
+	Redelivered   bool
+}
+
+func (m *Message) Raw() Message {
+	return *m
+}
+
+func (m *Message) Text() string {
+	return m.Content
+}
+
+func (m *Message) SetRaw(raw Message) {
+	*m = raw
+}
+
+func (m *Message) SetText(msg string) {
+	m.Content = msg
+}
+
+// GetCorrelationID returns the correlation-id property, implementing api.Correlated.
+func (m *Message) GetCorrelationID() string {
+	return m.CorrelationID
+}
+
+// SetCorrelationID sets the correlation-id property, implementing api.Correlated.
+func (m *Message) SetCorrelationID(id string) {
+	m.CorrelationID = id
+}
+
+// GetReplyTo returns the reply-to property, implementing api.Correlated.
+func (m *Message) GetReplyTo() string {
+	return m.ReplyTo
+}
+
+// SetReplyTo sets the reply-to property, implementing api.Correlated.
+func (m *Message) SetReplyTo(replyTo string) {
+	m.ReplyTo = replyTo
+}
+
+// GetProperty returns the named header as a string, implementing api.Properties.
+func (m *Message) GetProperty(key string) string {
+	value, ok := m.Headers[key]
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// SetProperty sets the named header, implementing api.Properties.
+func (m *Message) SetProperty(key string, value string) {
+	if m.Headers == nil {
+		m.Headers = make(map[string]interface{})
+	}
+	m.Headers[key] = value
+}
+
+// PropertyKeys returns the names of the headers, implementing api.Properties.
+func (m *Message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetMessageID returns the message-id property, implementing api.Identified.
+func (m *Message) GetMessageID() string {
+	return m.MessageID
+}
@@ -0,0 +1,62 @@
+package amqp
+
+import (
+	"testing"
+
+	"github.com/pgvanniekerk/ezQue/api"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaw(t *testing.T) {
+	message := &Message{
+		Content:       "testContent",
+		CorrelationID: "testCorrelation",
+	}
+
+	raw := message.Raw()
+
+	// check if the properties of the raw message match those of the original message
+	require.Equal(t, message.Content, raw.Content, "Raw Content does not match the original message's Content")
+	require.Equal(t, message.CorrelationID, raw.CorrelationID, "Raw CorrelationID does not match the original message's CorrelationID")
+}
+
+func TestText(t *testing.T) {
+	const content = "testContent"
+
+	message := &Message{}
+	message.SetText(content)
+
+	// Text() should return the Content property of the message
+	require.Equal(t, content, message.Text(), "Text does not return the correct message Content")
+}
+
+func TestCorrelated(t *testing.T) {
+	var correlated api.Correlated = &Message{}
+	correlated.SetCorrelationID("id")
+	correlated.SetReplyTo("replies")
+
+	// the api.Correlated methods should map onto the AMQP properties
+	message := correlated.(*Message)
+	require.Equal(t, "id", message.CorrelationID, "SetCorrelationID does not set CorrelationID")
+	require.Equal(t, "replies", message.ReplyTo, "SetReplyTo does not set ReplyTo")
+	require.Equal(t, "id", correlated.GetCorrelationID())
+	require.Equal(t, "replies", correlated.GetReplyTo())
+}
+
+func TestProperties(t *testing.T) {
+	var properties api.Properties = &Message{Headers: map[string]interface{}{"count": int32(3)}}
+	properties.SetProperty("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	// the api.Properties methods should map onto the AMQP headers
+	message := properties.(*Message)
+	require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", message.Headers["traceparent"])
+	require.Equal(t, "3", properties.GetProperty("count"))
+	require.Equal(t, "", properties.GetProperty("missing"))
+	require.Equal(t, []string{"count", "traceparent"}, properties.PropertyKeys())
+}
+
+func TestGetMessageID(t *testing.T) {
+	var identified api.Identified = &Message{MessageID: "id"}
+	require.Equal(t, "id", identified.GetMessageID())
+}
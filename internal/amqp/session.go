@@ -0,0 +1,157 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"sync"
+	"time"
+)
+
+// Connection is the subset of *amqp.Connection used by ezQue.
+type Connection interface {
+	Channel() (Channel, error)
+	IsClosed() bool
+	Close() error
+}
+
+// Channel is the subset of *amqp.Channel used by ezQue. *amqp.Channel
+// satisfies it as is.
+type Channel interface {
+	Confirm(noWait bool) error
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Ack(tag uint64, multiple bool) error
+	Nack(tag uint64, multiple bool, requeue bool) error
+	IsClosed() bool
+	Close() error
+}
+
+// Dialer opens a new connection to the broker. It is called on first use
+// and again whenever the connection has been lost.
+type Dialer func() (Connection, error)
+
+// Dial returns a Dialer that connects to the broker at url using amqp091-go.
+func Dial(url string, config amqp.Config) Dialer {
+	return func() (Connection, error) {
+		conn, err := amqp.DialConfig(url, config)
+		if err != nil {
+			return nil, err
+		}
+		return &connection{conn}, nil
+	}
+}
+
+// connection adapts *amqp.Connection to the Connection interface.
+type connection struct {
+	*amqp.Connection
+}
+
+func (c *connection) Channel() (Channel, error) {
+	return c.Connection.Channel()
+}
+
+// channel is an open Channel together with the state that was set
+// up on it. It is replaced as a whole when the channel is recovered.
+type channel struct {
+	Channel
+	confirms   *confirmer
+	deliveries <-chan amqp.Delivery
+}
+
+// session owns a connection and a single channel on it, re-opening
+// both transparently when they have been closed by the broker or lost.
+type session struct {
+	mu sync.Mutex
+
+	dial  Dialer
+	setup func(*channel) error
+
+	// recoveryInterval is the time waited between attempts to re-open
+	// a lost connection or channel.
+	recoveryInterval time.Duration
+
+	conn    Connection
+	current *channel
+}
+
+func newSession(dial Dialer, recoveryInterval time.Duration, setup func(*channel) error) *session {
+	return &session{
+		dial:             dial,
+		setup:            setup,
+		recoveryInterval: recoveryInterval,
+	}
+}
+
+// channel returns the session's open channel, recovering the connection and
+// channel first if either has been closed. It keeps retrying every
+// recoveryInterval until it succeeds or the context is done.
+func (s *session) channel(ctx context.Context) (*channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		ch, err := s.open()
+		if err == nil {
+			return ch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to recover channel: %w", err)
+		case <-time.After(s.recoveryInterval):
+		}
+	}
+}
+
+// open makes a single attempt at returning an open channel.
+func (s *session) open() (*channel, error) {
+
+	if s.current != nil && !s.current.IsClosed() {
+		return s.current, nil
+	}
+
+	if s.conn == nil || s.conn.IsClosed() {
+		conn, err := s.dial()
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+
+	amqpCh, err := s.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := &channel{Channel: amqpCh}
+	err = s.setup(ch)
+	if err != nil {
+		_ = amqpCh.Close()
+		return nil, err
+	}
+
+	s.current = ch
+	return ch, nil
+}
+
+// close closes the channel and connection, if open.
+func (s *session) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != nil && !s.current.IsClosed() {
+		_ = s.current.Close()
+	}
+	s.current = nil
+
+	if s.conn == nil || s.conn.IsClosed() {
+		s.conn = nil
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
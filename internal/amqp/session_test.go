@@ -0,0 +1,231 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBroker is an in-memory stand-in for an AMQP broker. It records what is
+// published and acknowledged, and lets tests feed deliveries to consumers.
+type fakeBroker struct {
+	mu sync.Mutex
+
+	dials    int
+	dialErr  error
+	channels []*fakeChannel
+
+	published    []amqp.Publishing
+	nackAll      bool
+	holdConfirms bool
+	acked        []uint64
+	nacked       []uint64
+}
+
+func (b *fakeBroker) dial() (Connection, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dials++
+	if b.dialErr != nil {
+		return nil, b.dialErr
+	}
+	return &fakeConnection{broker: b}, nil
+}
+
+// channel returns the most recently opened channel.
+func (b *fakeBroker) channel() *fakeChannel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.channels[len(b.channels)-1]
+}
+
+type fakeConnection struct {
+	broker *fakeBroker
+	closed bool
+}
+
+func (c *fakeConnection) Channel() (Channel, error) {
+	if c.closed {
+		return nil, amqp.ErrClosed
+	}
+	ch := &fakeChannel{broker: c.broker, deliveries: make(chan amqp.Delivery, 10)}
+	c.broker.mu.Lock()
+	c.broker.channels = append(c.broker.channels, ch)
+	c.broker.mu.Unlock()
+	return ch, nil
+}
+
+func (c *fakeConnection) IsClosed() bool { return c.closed }
+
+func (c *fakeConnection) Close() error {
+	c.closed = true
+	return nil
+}
+
+type fakeChannel struct {
+	mu     sync.Mutex
+	broker *fakeBroker
+
+	closed     bool
+	prefetch   int
+	tag        uint64
+	confirms   chan amqp.Confirmation
+	deliveries chan amqp.Delivery
+
+	// held are the confirms withheld while the broker holds confirms.
+	held []amqp.Confirmation
+}
+
+func (c *fakeChannel) Confirm(_ bool) error { return nil }
+
+func (c *fakeChannel) Qos(prefetchCount, _ int, _ bool) error {
+	c.prefetch = prefetchCount
+	return nil
+}
+
+func (c *fakeChannel) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	c.confirms = confirm
+	return confirm
+}
+
+func (c *fakeChannel) PublishWithContext(_ context.Context, _, _ string, _, _ bool, msg amqp.Publishing) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return amqp.ErrClosed
+	}
+	c.broker.mu.Lock()
+	c.broker.published = append(c.broker.published, msg)
+	nack, hold := c.broker.nackAll, c.broker.holdConfirms
+	c.broker.mu.Unlock()
+
+	c.tag++
+	confirm := amqp.Confirmation{DeliveryTag: c.tag, Ack: !nack}
+	if hold {
+		c.held = append(c.held, confirm)
+		return nil
+	}
+
+	c.confirms <- confirm
+	return nil
+}
+
+// releaseConfirms delivers the confirms withheld while the broker held them.
+func (c *fakeChannel) releaseConfirms() {
+	c.mu.Lock()
+	held := c.held
+	c.held = nil
+	c.mu.Unlock()
+	for _, confirm := range held {
+		c.confirms <- confirm
+	}
+}
+
+func (c *fakeChannel) Consume(_, _ string, _, _, _, _ bool, _ amqp.Table) (<-chan amqp.Delivery, error) {
+	return c.deliveries, nil
+}
+
+func (c *fakeChannel) Ack(tag uint64, _ bool) error {
+	if c.IsClosed() {
+		return amqp.ErrClosed
+	}
+	c.broker.mu.Lock()
+	defer c.broker.mu.Unlock()
+	c.broker.acked = append(c.broker.acked, tag)
+	return nil
+}
+
+func (c *fakeChannel) Nack(tag uint64, _ bool, _ bool) error {
+	if c.IsClosed() {
+		return amqp.ErrClosed
+	}
+	c.broker.mu.Lock()
+	defer c.broker.mu.Unlock()
+	c.broker.nacked = append(c.broker.nacked, tag)
+	return nil
+}
+
+func (c *fakeChannel) IsClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// Close closes the channel the way the broker would, closing the
+// confirmation and delivery channels handed out on it.
+func (c *fakeChannel) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	if c.confirms != nil {
+		close(c.confirms)
+	}
+	close(c.deliveries)
+	return nil
+}
+
+func TestSessionReusesOpenChannel(t *testing.T) {
+	broker := &fakeBroker{}
+	s := newSession(broker.dial, time.Millisecond, func(*channel) error { return nil })
+
+	ch1, err := s.channel(context.Background())
+	require.NoError(t, err)
+	ch2, err := s.channel(context.Background())
+	require.NoError(t, err)
+
+	require.Same(t, ch1, ch2, "An open channel should be reused")
+	require.Equal(t, 1, broker.dials)
+}
+
+func TestSessionRecoversClosedChannel(t *testing.T) {
+	broker := &fakeBroker{}
+	setups := 0
+	s := newSession(broker.dial, time.Millisecond, func(*channel) error {
+		setups++
+		return nil
+	})
+
+	ch1, err := s.channel(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, ch1.Close())
+
+	ch2, err := s.channel(context.Background())
+	require.NoError(t, err)
+
+	require.NotSame(t, ch1, ch2, "A closed channel should be replaced")
+	require.Equal(t, 2, setups, "The replacement channel should be set up again")
+	require.Equal(t, 1, broker.dials, "The connection is still open and should be reused")
+}
+
+func TestSessionRecoversClosedConnection(t *testing.T) {
+	broker := &fakeBroker{}
+	s := newSession(broker.dial, time.Millisecond, func(*channel) error { return nil })
+
+	_, err := s.channel(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, s.conn.Close())
+	require.NoError(t, s.current.Close())
+
+	_, err = s.channel(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, broker.dials, "A closed connection should be re-dialled")
+}
+
+func TestSessionRetriesUntilContextDone(t *testing.T) {
+	broker := &fakeBroker{dialErr: errors.New("connection refused")}
+	s := newSession(broker.dial, time.Millisecond, func(*channel) error { return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := s.channel(ctx)
+	require.ErrorContains(t, err, "connection refused")
+	require.Greater(t, broker.dials, 1, "Dialling should have been retried")
+}
@@ -0,0 +1,32 @@
+package jetstream
+
+import (
+	"context"
+	js "github.com/nats-io/nats.go/jetstream"
+	"github.com/pgvanniekerk/ezQue/api"
+	"time"
+)
+
+type DequeueMessage struct {
+	message  Message
+	msg      js.Msg
+	nakDelay time.Duration
+}
+
+func (d *DequeueMessage) Message() api.Message[Message] {
+	return &d.message
+}
+
+// Ack acknowledges the message and waits for the server to confirm the acknowledgement.
+func (d *DequeueMessage) Ack(ctx context.Context) error {
+	return d.msg.DoubleAck(ctx)
+}
+
+// NAck negatively acknowledges the message, asking the server to redeliver it
+// once the Dequeuer's nak delay has passed.
+func (d *DequeueMessage) NAck(_ context.Context) error {
+	if d.nakDelay > 0 {
+		return d.msg.NakWithDelay(d.nakDelay)
+	}
+	return d.msg.Nak()
+}
@@ -0,0 +1,108 @@
+package jetstream
+
+import (
+	"context"
+	"github.com/nats-io/nats.go"
+	js "github.com/nats-io/nats.go/jetstream"
+	"github.com/pgvanniekerk/ezQue/api"
+	"time"
+)
+
+func NewDequeuer(conn *nats.Conn, consumer js.Consumer, fetchWait time.Duration, nakDelay time.Duration) *Dequeuer {
+	return &Dequeuer{
+		conn:      conn,
+		consumer:  consumer,
+		fetchWait: fetchWait,
+		nakDelay:  nakDelay,
+	}
+}
+
+// Dequeuer pulls messages from a durable JetStream pull consumer, one at a time.
+type Dequeuer struct {
+
+	// conn is the NATS connection the consumer was created from.
+	// It is owned by the Dequeuer and closed on Disconnect.
+	conn *nats.Conn
+
+	consumer js.Consumer
+
+	// fetchWait is how long a single pull request waits for a message before
+	// it expires and a new one is issued.
+	fetchWait time.Duration
+
+	// nakDelay is how long the server waits before redelivering a NAcked message.
+	nakDelay time.Duration
+}
+
+// Dequeue fetches the next message from the consumer. It blocks until a message
+// is available or until the context is cancelled, in which case the context's
+// error is returned.
+func (d *Dequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		batch, err := d.consumer.Fetch(1, js.FetchMaxWait(d.fetchWait))
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case msg, ok := <-batch.Messages():
+			if !ok {
+				// The pull request expired without a message; issue another.
+				if err := batch.Error(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return d.newDequeueMessage(msg)
+
+		case <-ctx.Done():
+			// The pull request is still outstanding. Hand back any message
+			// it delivers, so it is not held until its ack wait expires.
+			go func() {
+				for msg := range batch.Messages() {
+					_ = msg.Nak()
+				}
+			}()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (d *Dequeuer) newDequeueMessage(msg js.Msg) (api.DequeueMessage[Message], error) {
+
+	meta, err := msg.Metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	message := Message{
+		Content:      string(msg.Data()),
+		Headers:      msg.Headers(),
+		Subject:      msg.Subject(),
+		Sequence:     meta.Sequence.Stream,
+		NumDelivered: meta.NumDelivered,
+		Timestamp:    meta.Timestamp,
+	}
+
+	deqMsg := &DequeueMessage{
+		message:  message,
+		msg:      msg,
+		nakDelay: d.nakDelay,
+	}
+
+	return deqMsg, nil
+}
+
+func (d *Dequeuer) Disconnect(_ context.Context) error {
+
+	d.conn.Close()
+
+	d.conn = nil
+	d.consumer = nil
+	return nil
+}
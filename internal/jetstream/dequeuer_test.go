@@ -0,0 +1,158 @@
+package jetstream
+
+import (
+	"context"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	js "github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+// runServer starts an embedded NATS server with JetStream enabled and a
+// "TEXT_MSGS" stream capturing the "text.msgs" subject.
+func runServer(t *testing.T) (*server.Server, *nats.Conn, js.JetStream) {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := js.New(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.CreateStream(context.Background(), js.StreamConfig{
+		Name:     "TEXT_MSGS",
+		Subjects: []string{"text.msgs"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv, conn, stream
+}
+
+func TestDequeuerTestSuite(t *testing.T) {
+	suite.Run(t, new(DequeuerTestSuite))
+}
+
+type DequeuerTestSuite struct {
+	suite.Suite
+	srv      *server.Server
+	conn     *nats.Conn
+	stream   js.JetStream
+	dequeuer *Dequeuer
+}
+
+func (suite *DequeuerTestSuite) SetupTest() {
+	suite.srv, suite.conn, suite.stream = runServer(suite.T())
+
+	consumer, err := suite.stream.CreateOrUpdateConsumer(context.Background(), "TEXT_MSGS", js.ConsumerConfig{
+		Durable:       "test_consumer",
+		FilterSubject: "text.msgs",
+		AckPolicy:     js.AckExplicitPolicy,
+	})
+	suite.Require().NoError(err)
+
+	consConn, err := nats.Connect(suite.srv.ClientURL())
+	suite.Require().NoError(err)
+	suite.dequeuer = NewDequeuer(consConn, consumer, 100*time.Millisecond, 0)
+}
+
+func (suite *DequeuerTestSuite) TearDownTest() {
+	_ = suite.dequeuer.Disconnect(context.Background())
+	suite.conn.Close()
+	suite.srv.Shutdown()
+}
+
+func (suite *DequeuerTestSuite) publish(content string) {
+	_, err := suite.stream.PublishMsg(context.Background(), &nats.Msg{
+		Subject: "text.msgs",
+		Data:    []byte(content),
+		Header:  nats.Header{"h": []string{content}},
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueEmptyStream() {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Nil(deqMsg, "DequeueMessage should be nil when there is an error")
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndNAck() {
+	suite.publish("test message")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message")
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.Equal([]string{"test message"}, deqMsg.Message().Raw().Headers["h"])
+	suite.Equal(uint64(1), deqMsg.Message().Raw().NumDelivered)
+
+	suite.Require().NoError(deqMsg.NAck(ctx), "Failed to NAck message")
+
+	// The message must be redelivered
+	deqMsg2, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to re-dequeue message")
+	suite.Equal("test message", deqMsg2.Message().Text(), "Re-dequeued message should still be identical")
+	suite.Equal(uint64(2), deqMsg2.Message().Raw().NumDelivered)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndAck() {
+	suite.publish("test message")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message")
+	suite.Require().NoError(deqMsg.Ack(ctx), "Failed to Ack message")
+
+	// The message must not be delivered again
+	reCtx, reCancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer reCancel()
+
+	newDeqMsg, err := suite.dequeuer.Dequeue(reCtx)
+	suite.Nil(newDeqMsg)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (suite *DequeuerTestSuite) TestNAckWithDelay() {
+	suite.dequeuer.nakDelay = 500 * time.Millisecond
+	suite.publish("test message")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Require().NoError(deqMsg.NAck(ctx))
+
+	// The message must not be redelivered before the delay has passed
+	start := time.Now()
+	deqMsg2, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg2.Message().Text())
+	suite.GreaterOrEqual(time.Since(start), 400*time.Millisecond)
+}
@@ -0,0 +1,64 @@
+package jetstream
+
+import (
+	"context"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	js "github.com/nats-io/nats.go/jetstream"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+func NewEnqueuer(conn *nats.Conn, stream js.JetStream, subject string) *Enqueuer {
+	return &Enqueuer{
+		conn:    conn,
+		stream:  stream,
+		subject: subject,
+	}
+}
+
+// Enqueuer publishes messages to a subject captured by a JetStream stream.
+type Enqueuer struct {
+
+	// conn is the NATS connection the JetStream context was created from.
+	// It is owned by the Enqueuer and closed on Disconnect.
+	conn *nats.Conn
+
+	stream js.JetStream
+
+	// The subject that the Enqueuer is bound to.
+	subject string
+}
+
+// NewMessage returns a new, empty instance of `Message` that implements the `api.Message` interface.
+func (e *Enqueuer) NewMessage() api.Message[Message] {
+	return &Message{}
+}
+
+// Enqueue publishes a message to the bound subject and waits for the stream to
+// acknowledge that it has been stored.
+func (e *Enqueuer) Enqueue(ctx context.Context, msg api.Message[Message]) error {
+
+	raw := msg.Raw()
+	natsMsg := &nats.Msg{
+		Subject: e.subject,
+		Data:    []byte(raw.Content),
+		Header:  nats.Header(raw.Headers),
+	}
+
+	_, err := e.stream.PublishMsg(ctx, natsMsg)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Enqueuer) Disconnect(_ context.Context) error {
+
+	e.conn.Close()
+
+	e.conn = nil
+	e.stream = nil
+	e.subject = ""
+	return nil
+}
@@ -0,0 +1,70 @@
+package jetstream
+
+import (
+	"context"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	js "github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type EnqueuerTestSuite struct {
+	suite.Suite
+	srv      *server.Server
+	conn     *nats.Conn
+	stream   js.JetStream
+	enqueuer *Enqueuer
+}
+
+func TestEnqueuerTestSuite(t *testing.T) {
+	suite.Run(t, new(EnqueuerTestSuite))
+}
+
+func (suite *EnqueuerTestSuite) SetupTest() {
+	suite.srv, suite.conn, suite.stream = runServer(suite.T())
+
+	pubConn, err := nats.Connect(suite.srv.ClientURL())
+	suite.Require().NoError(err)
+	pubStream, err := js.New(pubConn)
+	suite.Require().NoError(err)
+	suite.enqueuer = NewEnqueuer(pubConn, pubStream, "text.msgs")
+}
+
+func (suite *EnqueuerTestSuite) TearDownTest() {
+	_ = suite.enqueuer.Disconnect(context.Background())
+	suite.conn.Close()
+	suite.srv.Shutdown()
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	message := &Message{
+		Content: "test message",
+		Headers: map[string][]string{"h": {"v"}},
+	}
+	err := suite.enqueuer.Enqueue(ctx, message)
+	suite.NoError(err, "Failed to enqueue message")
+
+	// Read the message back from the stream
+	stored, err := suite.stream.Stream(ctx, "TEXT_MSGS")
+	suite.Require().NoError(err)
+	raw, err := stored.GetMsg(ctx, 1)
+	suite.Require().NoError(err)
+
+	suite.Equal("test message", string(raw.Data), "The content of the stored message does not match the original message.")
+	suite.Equal("v", raw.Header.Get("h"), "The headers of the stored message do not match the original message.")
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_Error() {
+	enqueuer := NewEnqueuer(suite.enqueuer.conn, suite.enqueuer.stream, "no.stream.here")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := enqueuer.Enqueue(ctx, &Message{Content: "test message"})
+	suite.Error(err, "Expected error during enqueue because no stream captures the subject.")
+}
@@ -0,0 +1,79 @@
+package jetstream
+
+import (
+	"github.com/nats-io/nats.go"
+	"sort"
+	"time"
+)
+
+// Message is a JetStream message as seen by ezQue. Headers are carried as NATS
+// message headers. Sequence, NumDelivered and Timestamp are populated on Dequeue
+// and are ignored on Enqueue.
+type Message struct {
+	Content      string
+	Headers      map[string][]string
+	Subject      string
+	Sequence     uint64
+	NumDelivered uint64
+	Timestamp    time.Time
+}
+
+func (m *Message) Raw() Message {
+	return *m
+}
+
+func (m *Message) Text() string {
+	return m.Content
+}
+
+func (m *Message) SetRaw(raw Message) {
+	*m = raw
+}
+
+func (m *Message) SetText(msg string) {
+	m.Content = msg
+}
+
+// GetIdempotencyKey returns the Nats-Msg-Id header, implementing api.Idempotent.
+func (m *Message) GetIdempotencyKey() string {
+	return nats.Header(m.Headers).Get(nats.MsgIdHdr)
+}
+
+// SetIdempotencyKey sets the Nats-Msg-Id header, implementing api.Idempotent. The
+// stream drops messages whose ID it has already stored within its duplicate window.
+func (m *Message) SetIdempotencyKey(key string) {
+	if m.Headers == nil {
+		m.Headers = make(map[string][]string)
+	}
+	nats.Header(m.Headers).Set(nats.MsgIdHdr, key)
+}
+
+// GetProperty returns the first value of the named header, implementing
+// api.Properties.
+func (m *Message) GetProperty(key string) string {
+	return nats.Header(m.Headers).Get(key)
+}
+
+// SetProperty sets the named header, implementing api.Properties.
+func (m *Message) SetProperty(key string, value string) {
+	if m.Headers == nil {
+		m.Headers = make(map[string][]string)
+	}
+	nats.Header(m.Headers).Set(key, value)
+}
+
+// PropertyKeys returns the names of the headers, implementing api.Properties.
+func (m *Message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetEnqueuedAt returns when the stream stored the message, implementing
+// api.Timestamped.
+func (m *Message) GetEnqueuedAt() time.Time {
+	return m.Timestamp
+}
@@ -0,0 +1,52 @@
+package jetstream
+
+import (
+	"testing"
+
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaw(t *testing.T) {
+	message := &Message{
+		Content: "testContent",
+		Headers: map[string][]string{"h": {"v"}},
+	}
+
+	raw := message.Raw()
+
+	// check if the properties of the raw message match those of the original message
+	require.Equal(t, message.Content, raw.Content, "Raw Content does not match the original message's Content")
+	require.Equal(t, message.Headers, raw.Headers, "Raw Headers do not match the original message's Headers")
+}
+
+func TestText(t *testing.T) {
+	const content = "testContent"
+
+	message := &Message{}
+	message.SetText(content)
+
+	// Text() should return the Content property of the message
+	require.Equal(t, content, message.Text(), "Text does not return the correct message Content")
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	message := &Message{}
+	message.SetIdempotencyKey("order-1")
+
+	// the idempotency key is sent as the Nats-Msg-Id header
+	require.Equal(t, []string{"order-1"}, message.Headers["Nats-Msg-Id"])
+	require.Equal(t, "order-1", message.GetIdempotencyKey())
+}
+
+func TestProperties(t *testing.T) {
+	var properties api.Properties = &Message{Headers: map[string][]string{"count": {"3", "4"}}}
+	properties.SetProperty("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	// the api.Properties methods should map onto the NATS headers
+	message := properties.(*Message)
+	require.Equal(t, []string{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}, message.Headers["traceparent"])
+	require.Equal(t, "3", properties.GetProperty("count"))
+	require.Equal(t, "", properties.GetProperty("missing"))
+	require.Equal(t, []string{"count", "traceparent"}, properties.PropertyKeys())
+}
@@ -0,0 +1,38 @@
+package kafka
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+type DequeueMessage struct {
+	message Message
+	record  *kgo.Record
+	client  *kgo.Client
+}
+
+func (d *DequeueMessage) Message() api.Message[Message] {
+	return &d.message
+}
+
+// Ack commits the offset following the record to the consumer group. Kafka
+// offsets are cumulative per partition, so acknowledging a record implicitly
+// acknowledges every earlier record in the same partition.
+func (d *DequeueMessage) Ack(ctx context.Context) error {
+	return d.client.CommitRecords(ctx, d.record)
+}
+
+// NAck seeks the record's partition back to the record, so that it and every
+// record after it in the partition are delivered again by the next Dequeue.
+func (d *DequeueMessage) NAck(_ context.Context) error {
+	d.client.SetOffsets(map[string]map[int32]kgo.EpochOffset{
+		d.record.Topic: {
+			d.record.Partition: {
+				Epoch:  d.record.LeaderEpoch,
+				Offset: d.record.Offset,
+			},
+		},
+	})
+	return nil
+}
@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func NewDequeuer(client *kgo.Client, topic string) *Dequeuer {
+	return &Dequeuer{
+		client: client,
+		topic:  topic,
+	}
+}
+
+// Dequeuer consumes messages from a Kafka topic as a member of a consumer group.
+// The client it is given is expected to be configured with the consumer group,
+// the topic to consume and auto-commit disabled, so that offsets only move on Ack.
+type Dequeuer struct {
+
+	// client is the Kafka client used to fetch records. It is
+	// safe for concurrent use.
+	client *kgo.Client
+
+	// The Kafka topic that the Dequeuer is bound to.
+	topic string
+}
+
+// Dequeue fetches the next record from any partition assigned to this consumer.
+// It blocks until a record is available or until the context is cancelled, in
+// which case the context's error is returned.
+func (d *Dequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	for {
+		fetches := d.client.PollRecords(ctx, 1)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := fetches.Err(); err != nil {
+			return nil, err
+		}
+
+		records := fetches.Records()
+		if len(records) == 0 {
+			continue
+		}
+
+		record := records[0]
+		message := Message{
+			Key:       record.Key,
+			Content:   string(record.Value),
+			Headers:   make([]Header, 0, len(record.Headers)),
+			Topic:     record.Topic,
+			Partition: record.Partition,
+			Offset:    record.Offset,
+			Timestamp: record.Timestamp,
+		}
+		for _, h := range record.Headers {
+			message.Headers = append(message.Headers, Header{Key: h.Key, Value: h.Value})
+		}
+
+		deqMsg := &DequeueMessage{
+			message: message,
+			record:  record,
+			client:  d.client,
+		}
+
+		return deqMsg, nil
+	}
+}
+
+func (d *Dequeuer) Disconnect(_ context.Context) error {
+
+	d.client.Close()
+
+	d.client = nil
+	d.topic = ""
+	return nil
+}
@@ -0,0 +1,124 @@
+package kafka
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"testing"
+	"time"
+)
+
+func TestDequeuerTestSuite(t *testing.T) {
+	suite.Run(t, new(DequeuerTestSuite))
+}
+
+type DequeuerTestSuite struct {
+	suite.Suite
+	cluster *kfake.Cluster
+	topic   string
+}
+
+// SetupTest starts a fresh in-process Kafka cluster for every test, so that
+// committed offsets of one test do not leak into the next.
+func (suite *DequeuerTestSuite) SetupTest() {
+	suite.topic = "text_msg_topic"
+
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, suite.topic))
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	suite.cluster = cluster
+}
+
+func (suite *DequeuerTestSuite) TearDownTest() {
+	suite.cluster.Close()
+}
+
+// newDequeuer builds a Dequeuer the same way the kafka connector does.
+func (suite *DequeuerTestSuite) newDequeuer() *Dequeuer {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(suite.cluster.ListenAddrs()...),
+		kgo.ConsumeTopics(suite.topic),
+		kgo.ConsumerGroup("test_group"),
+		kgo.DisableAutoCommit(),
+	)
+	suite.Require().NoError(err)
+	return NewDequeuer(client, suite.topic)
+}
+
+// produce writes the given values to the test topic.
+func (suite *DequeuerTestSuite) produce(values ...string) {
+	client, err := kgo.NewClient(kgo.SeedBrokers(suite.cluster.ListenAddrs()...), kgo.DefaultProduceTopic(suite.topic))
+	suite.Require().NoError(err)
+	defer client.Close()
+
+	for _, v := range values {
+		err = client.ProduceSync(context.Background(), &kgo.Record{
+			Key:     []byte("key"),
+			Value:   []byte(v),
+			Headers: []kgo.RecordHeader{{Key: "h", Value: []byte(v)}},
+		}).FirstErr()
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *DequeuerTestSuite) TestDequeueEmptyTopic() {
+	dequeuer := suite.newDequeuer()
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	deqMsg, err := dequeuer.Dequeue(ctx)
+
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Nil(deqMsg, "DequeueMessage should be nil when there is an error")
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndNAck() {
+	suite.produce("first", "second")
+
+	dequeuer := suite.newDequeuer()
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message")
+	suite.Equal("first", deqMsg.Message().Text())
+	suite.Equal([]byte("key"), deqMsg.Message().Raw().Key)
+	suite.Equal([]Header{{Key: "h", Value: []byte("first")}}, deqMsg.Message().Raw().Headers)
+
+	// NAck seeks back, so the same record must be delivered again
+	suite.Require().NoError(deqMsg.NAck(ctx), "Failed to NAck message")
+
+	deqMsg2, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to re-dequeue message")
+	suite.Equal("first", deqMsg2.Message().Text(), "Re-dequeued message should still be identical")
+	suite.Equal(deqMsg.Message().Raw().Offset, deqMsg2.Message().Raw().Offset)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndAck() {
+	suite.produce("first", "second")
+
+	dequeuer := suite.newDequeuer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message")
+	suite.Equal("first", deqMsg.Message().Text())
+	suite.Require().NoError(deqMsg.Ack(ctx), "Failed to Ack message")
+
+	// Reconnect to the group; consumption must resume after the committed offset
+	suite.Require().NoError(dequeuer.Disconnect(ctx))
+	dequeuer = suite.newDequeuer()
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	deqMsg2, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message after reconnect")
+	suite.Equal("second", deqMsg2.Message().Text(), "Acked message should not be delivered again")
+}
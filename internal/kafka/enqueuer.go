@@ -0,0 +1,64 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func NewEnqueuer(client *kgo.Client, topic string) *Enqueuer {
+	return &Enqueuer{
+		client: client,
+		topic:  topic,
+	}
+}
+
+// Enqueuer produces messages to a single Kafka topic.
+type Enqueuer struct {
+
+	// client is the Kafka client used to produce records. It is
+	// safe for concurrent use.
+	client *kgo.Client
+
+	// The Kafka topic that the Enqueuer is bound to.
+	topic string
+}
+
+// NewMessage returns a new, empty instance of `Message` that implements the `api.Message` interface.
+func (e *Enqueuer) NewMessage() api.Message[Message] {
+	return &Message{}
+}
+
+// Enqueue produces a message to the bound topic and waits until the broker has
+// acknowledged it. Records with the same Key are produced to the same partition,
+// so they are dequeued in the order they were enqueued.
+func (e *Enqueuer) Enqueue(ctx context.Context, msg api.Message[Message]) error {
+
+	raw := msg.Raw()
+	record := &kgo.Record{
+		Topic:   e.topic,
+		Key:     raw.Key,
+		Value:   []byte(raw.Content),
+		Headers: make([]kgo.RecordHeader, 0, len(raw.Headers)),
+	}
+	for _, h := range raw.Headers {
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: h.Key, Value: h.Value})
+	}
+
+	err := e.client.ProduceSync(ctx, record).FirstErr()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Enqueuer) Disconnect(_ context.Context) error {
+
+	e.client.Close()
+
+	e.client = nil
+	e.topic = ""
+	return nil
+}
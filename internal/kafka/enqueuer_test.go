@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"testing"
+	"time"
+)
+
+type EnqueuerTestSuite struct {
+	suite.Suite
+	cluster *kfake.Cluster
+	topic   string
+}
+
+func TestEnqueuerTestSuite(t *testing.T) {
+	suite.Run(t, new(EnqueuerTestSuite))
+}
+
+func (suite *EnqueuerTestSuite) SetupSuite() {
+	suite.topic = "text_msg_topic"
+
+	// Start an in-process Kafka cluster with a single partition topic
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, suite.topic))
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	suite.cluster = cluster
+}
+
+func (suite *EnqueuerTestSuite) TearDownSuite() {
+	suite.cluster.Close()
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue() {
+	client, err := kgo.NewClient(kgo.SeedBrokers(suite.cluster.ListenAddrs()...))
+	suite.Require().NoError(err)
+	enqueuer := NewEnqueuer(client, suite.topic)
+	defer func() { _ = enqueuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	message := &Message{
+		Key:     []byte("key"),
+		Content: "test message",
+		Headers: []Header{{Key: "h", Value: []byte("v")}},
+	}
+	err = enqueuer.Enqueue(ctx, message)
+	suite.NoError(err, "Failed to enqueue message")
+
+	// Read the record back directly from the partition
+	consumer, err := kgo.NewClient(
+		kgo.SeedBrokers(suite.cluster.ListenAddrs()...),
+		kgo.ConsumeTopics(suite.topic),
+	)
+	suite.Require().NoError(err)
+	defer consumer.Close()
+
+	fetches := consumer.PollRecords(ctx, 1)
+	suite.Require().NoError(fetches.Err())
+	records := fetches.Records()
+	suite.Require().Len(records, 1)
+
+	suite.Equal("test message", string(records[0].Value), "The content of the produced record does not match the original message.")
+	suite.Equal([]byte("key"), records[0].Key, "The key of the produced record does not match the original message.")
+	suite.Equal([]kgo.RecordHeader{{Key: "h", Value: []byte("v")}}, records[0].Headers, "The headers of the produced record do not match the original message.")
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_disconnect() {
+	client, err := kgo.NewClient(kgo.SeedBrokers(suite.cluster.ListenAddrs()...))
+	suite.Require().NoError(err)
+	enqueuer := NewEnqueuer(client, suite.topic)
+	defer func() { _ = enqueuer.Disconnect(context.Background()) }()
+
+	// Create a context with cancel
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = enqueuer.Enqueue(ctx, &Message{Content: "test message"})
+
+	// Expect error due to context being cancelled
+	suite.Error(err, "Expected an error when attempting to enqueue with a cancelled context")
+}
@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"sort"
+	"time"
+)
+
+// Header is a single Kafka record header. Kafka allows a key to be
+// repeated, so headers are kept as an ordered slice rather than a map.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is a Kafka record as seen by ezQue. Key determines the partition
+// the record is produced to, and therefore the ordering guarantees it gets.
+// Topic, Partition, Offset and Timestamp are populated on Dequeue and are
+// ignored on Enqueue.
+type Message struct {
+	Key       []byte
+	Content   string
+	Headers   []Header
+	Topic     string
+	Partition int32
+	Offset    int64
+	Timestamp time.Time
+}
+
+func (m *Message) Raw() Message {
+	return *m
+}
+
+func (m *Message) Text() string {
+	return m.Content
+}
+
+func (m *Message) SetRaw(raw Message) {
+	*m = raw
+}
+
+func (m *Message) SetText(msg string) {
+	m.Content = msg
+}
+
+// GetProperty returns the value of the last header with the given key,
+// implementing api.Properties.
+func (m *Message) GetProperty(key string) string {
+	for i := len(m.Headers) - 1; i >= 0; i-- {
+		if m.Headers[i].Key == key {
+			return string(m.Headers[i].Value)
+		}
+	}
+	return ""
+}
+
+// SetProperty replaces any headers with the given key by a single header,
+// implementing api.Properties.
+func (m *Message) SetProperty(key string, value string) {
+	headers := make([]Header, 0, len(m.Headers)+1)
+	for _, header := range m.Headers {
+		if header.Key != key {
+			headers = append(headers, header)
+		}
+	}
+	m.Headers = append(headers, Header{Key: key, Value: []byte(value)})
+}
+
+// PropertyKeys returns the distinct header keys, implementing api.Properties.
+func (m *Message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.Headers))
+	seen := make(map[string]bool, len(m.Headers))
+	for _, header := range m.Headers {
+		if !seen[header.Key] {
+			seen[header.Key] = true
+			keys = append(keys, header.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetEnqueuedAt returns the record timestamp, implementing api.Timestamped.
+func (m *Message) GetEnqueuedAt() time.Time {
+	return m.Timestamp
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaw(t *testing.T) {
+	message := &Message{
+		Key:     []byte("testKey"),
+		Content: "testContent",
+		Headers: []Header{{Key: "h", Value: []byte("v")}},
+	}
+
+	raw := message.Raw()
+
+	// check if the properties of the raw message match those of the original message
+	require.Equal(t, message.Key, raw.Key, "Raw Key does not match the original message's Key")
+	require.Equal(t, message.Content, raw.Content, "Raw Content does not match the original message's Content")
+	require.Equal(t, message.Headers, raw.Headers, "Raw Headers do not match the original message's Headers")
+}
+
+func TestSetRaw(t *testing.T) {
+	raw := Message{
+		Key:     []byte("testKey"),
+		Content: "testContent",
+	}
+
+	message := &Message{}
+	message.SetRaw(raw)
+
+	require.Equal(t, raw, message.Raw(), "SetRaw did not replace the message")
+}
+
+func TestText(t *testing.T) {
+	const content = "testContent"
+
+	message := &Message{}
+	message.SetText(content)
+
+	// Text() should return the Content property of the message
+	require.Equal(t, content, message.Text(), "Text does not return the correct message Content")
+}
+
+func TestProperties(t *testing.T) {
+	var properties api.Properties = &Message{Headers: []Header{
+		{Key: "count", Value: []byte("2")},
+		{Key: "count", Value: []byte("3")},
+	}}
+	properties.SetProperty("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	// the api.Properties methods should map onto the record headers
+	message := properties.(*Message)
+	require.Equal(t, Header{Key: "traceparent", Value: []byte("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")}, message.Headers[len(message.Headers)-1])
+	require.Equal(t, "3", properties.GetProperty("count"))
+	require.Equal(t, "", properties.GetProperty("missing"))
+	require.Equal(t, []string{"count", "traceparent"}, properties.PropertyKeys())
+
+	// setting a repeated key should leave a single header behind
+	properties.SetProperty("count", "4")
+	require.Len(t, message.Headers, 2)
+	require.Equal(t, "4", properties.GetProperty("count"))
+}
@@ -0,0 +1,320 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// defaultPayloadType is the payload type the Enqueuer and Dequeuer work with.
+const defaultPayloadType = "SYS.AQ$_JMS_TEXT_MESSAGE"
+
+// Oracle errors that the Ensure variants treat as already done.
+const (
+	errQueueTableExists   = "ORA-24001"
+	errQueueTableNotFound = "ORA-24002"
+	errQueueExists        = "ORA-24006"
+	errQueueNotFound      = "ORA-24010"
+	errNameInUse          = "ORA-00955"
+)
+
+// errUniqueViolated is raised when a key is inserted into an idempotency table again.
+const errUniqueViolated = "ORA-00001"
+
+// Privilege is a queue privilege that can be granted with Grant.
+type Privilege string
+
+const (
+	PrivilegeEnqueue Privilege = "ENQUEUE"
+	PrivilegeDequeue Privilege = "DEQUEUE"
+	PrivilegeAll     Privilege = "ALL"
+)
+
+// QueueTableOptions holds the settings of a queue table. Zero values use the
+// DBMS_AQADM defaults.
+type QueueTableOptions struct {
+
+	// PayloadType is the type of the messages stored in the queue table. It
+	// defaults to SYS.AQ$_JMS_TEXT_MESSAGE, which ezQue enqueues and dequeues.
+	PayloadType string
+
+	// MultipleConsumers allows queues in the table to have subscribers.
+	MultipleConsumers bool
+
+	// StorageClause is appended to the CREATE TABLE statement, for example
+	// to choose a tablespace.
+	StorageClause string
+}
+
+// QueueOptions holds the settings of a queue. With CreateQueue zero values
+// use the DBMS_AQADM defaults, and with AlterQueue they leave the setting
+// unchanged.
+type QueueOptions struct {
+
+	// MaxRetries is the number of times a message can be rolled back before
+	// it is moved to the exception queue.
+	MaxRetries int
+
+	// RetryDelay is how long a rolled back message waits before it can be
+	// dequeued again. It is rounded down to whole seconds.
+	RetryDelay time.Duration
+
+	// RetentionTime is how long dequeued messages are kept in the queue table.
+	// It is rounded down to whole seconds, and a negative value keeps them forever.
+	RetentionTime time.Duration
+}
+
+func NewAdmin(db *sql.DB) *Admin {
+	return &Admin{
+		db: db,
+	}
+}
+
+// Admin creates, alters and drops Oracle Advanced Queues and their queue tables
+// through DBMS_AQADM. The Ensure variants are idempotent, so that services can
+// provision their queues at startup.
+type Admin struct {
+
+	// db is a pointer to the SQL database connection. Note, this acts
+	// as a connection pool by default, and is safe for concurrent use.
+	db *sql.DB
+}
+
+// CreateQueueTable creates a queue table.
+func (a *Admin) CreateQueueTable(ctx context.Context, queueTable string, opts QueueTableOptions) error {
+
+	payloadType := opts.PayloadType
+	if payloadType == "" {
+		payloadType = defaultPayloadType
+	}
+
+	_, err := a.db.ExecContext(ctx, createQueueTableSQL,
+		queueTable,
+		payloadType,
+		boolInt(opts.MultipleConsumers),
+		nullString(opts.StorageClause),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create queue table %s: %w", queueTable, err)
+	}
+
+	return nil
+}
+
+// EnsureQueueTable creates a queue table if it does not exist yet.
+func (a *Admin) EnsureQueueTable(ctx context.Context, queueTable string, opts QueueTableOptions) error {
+	return ignoreOracleError(a.CreateQueueTable(ctx, queueTable, opts), errQueueTableExists)
+}
+
+// CreateIdempotencyTable creates a table for the idempotency keys of enqueued
+// messages, to be set on the Enqueuer with SetIdempotencyTable.
+func (a *Admin) CreateIdempotencyTable(ctx context.Context, table string) error {
+
+	table, err := IdempotencyTableName(table)
+	if err != nil {
+		return fmt.Errorf("oraaq: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, fmt.Sprintf(IdempotencyTableSQL, table))
+	if err != nil {
+		return fmt.Errorf("failed to create idempotency table %s: %w", table, err)
+	}
+
+	return nil
+}
+
+// EnsureIdempotencyTable creates a table for idempotency keys if it does not exist yet.
+func (a *Admin) EnsureIdempotencyTable(ctx context.Context, table string) error {
+	return ignoreOracleError(a.CreateIdempotencyTable(ctx, table), errNameInUse)
+}
+
+// DeleteIdempotencyKeysBefore deletes the keys inserted into an idempotency table
+// before the given time, returning how many were deleted, so that the table does not
+// grow forever. Keys should be kept for longer than an enqueue may be retried.
+func (a *Admin) DeleteIdempotencyKeysBefore(ctx context.Context, table string, before time.Time) (int64, error) {
+
+	table, err := IdempotencyTableName(table)
+	if err != nil {
+		return 0, fmt.Errorf("oraaq: %w", err)
+	}
+
+	result, err := a.db.ExecContext(ctx, fmt.Sprintf(DeleteIdempotencyKeysBeforeSQL, table), before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete from idempotency table %s: %w", table, oracleError(err))
+	}
+
+	return result.RowsAffected()
+}
+
+// DropQueueTable drops a queue table. If force is true the queues in the table
+// are stopped and dropped first, otherwise the table must not hold any queues.
+func (a *Admin) DropQueueTable(ctx context.Context, queueTable string, force bool) error {
+
+	_, err := a.db.ExecContext(ctx, dropQueueTableSQL, queueTable, boolInt(force))
+	if err != nil {
+		return fmt.Errorf("failed to drop queue table %s: %w", queueTable, err)
+	}
+
+	return nil
+}
+
+// EnsureQueueTableDropped drops a queue table, along with its queues, if it exists.
+func (a *Admin) EnsureQueueTableDropped(ctx context.Context, queueTable string) error {
+	return ignoreOracleError(a.DropQueueTable(ctx, queueTable, true), errQueueTableNotFound)
+}
+
+// CreateQueue creates a queue in an existing queue table. The queue must be
+// started with StartQueue before it can be used.
+func (a *Admin) CreateQueue(ctx context.Context, queue string, queueTable string, opts QueueOptions) error {
+
+	_, err := a.db.ExecContext(ctx, createQueueSQL,
+		queue,
+		queueTable,
+		nullInt(opts.MaxRetries),
+		seconds(opts.RetryDelay),
+		seconds(opts.RetentionTime),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create queue %s: %w", queue, err)
+	}
+
+	return nil
+}
+
+// EnsureQueue creates a queue, and its queue table with default options, if they
+// do not exist yet, and starts the queue. The options of an existing queue are
+// left as they are.
+func (a *Admin) EnsureQueue(ctx context.Context, queue string, queueTable string, opts QueueOptions) error {
+
+	err := a.EnsureQueueTable(ctx, queueTable, QueueTableOptions{})
+	if err != nil {
+		return err
+	}
+
+	err = ignoreOracleError(a.CreateQueue(ctx, queue, queueTable, opts), errQueueExists)
+	if err != nil {
+		return err
+	}
+
+	return a.StartQueue(ctx, queue)
+}
+
+// AlterQueue changes the options of a queue. Zero options are left unchanged.
+func (a *Admin) AlterQueue(ctx context.Context, queue string, opts QueueOptions) error {
+
+	_, err := a.db.ExecContext(ctx, alterQueueSQL,
+		queue,
+		nullInt(opts.MaxRetries),
+		nullInt(seconds(opts.RetryDelay)),
+		nullInt(seconds(opts.RetentionTime)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to alter queue %s: %w", queue, err)
+	}
+
+	return nil
+}
+
+// StartQueue enables both enqueueing to and dequeueing from a queue.
+func (a *Admin) StartQueue(ctx context.Context, queue string) error {
+
+	_, err := a.db.ExecContext(ctx, startQueueSQL, queue)
+	if err != nil {
+		return fmt.Errorf("failed to start queue %s: %w", queue, err)
+	}
+
+	return nil
+}
+
+// StopQueue disables both enqueueing to and dequeueing from a queue. It waits
+// for outstanding transactions on the queue to complete.
+func (a *Admin) StopQueue(ctx context.Context, queue string) error {
+
+	_, err := a.db.ExecContext(ctx, stopQueueSQL, queue)
+	if err != nil {
+		return fmt.Errorf("failed to stop queue %s: %w", queue, err)
+	}
+
+	return nil
+}
+
+// DropQueue drops a queue, which must have been stopped.
+func (a *Admin) DropQueue(ctx context.Context, queue string) error {
+
+	_, err := a.db.ExecContext(ctx, dropQueueSQL, queue)
+	if err != nil {
+		return fmt.Errorf("failed to drop queue %s: %w", queue, err)
+	}
+
+	return nil
+}
+
+// EnsureQueueDropped stops and drops a queue if it exists.
+func (a *Admin) EnsureQueueDropped(ctx context.Context, queue string) error {
+
+	err := ignoreOracleError(a.StopQueue(ctx, queue), errQueueNotFound)
+	if err != nil {
+		return err
+	}
+
+	return ignoreOracleError(a.DropQueue(ctx, queue), errQueueNotFound)
+}
+
+// Grant grants a privilege on a queue to a database user or role. Granting a
+// privilege that is already held has no effect.
+func (a *Admin) Grant(ctx context.Context, privilege Privilege, queue string, grantee string) error {
+
+	_, err := a.db.ExecContext(ctx, grantQueuePrivilegeSQL, string(privilege), queue, grantee)
+	if err != nil {
+		return fmt.Errorf("failed to grant %s on queue %s to %s: %w", privilege, queue, grantee, err)
+	}
+
+	return nil
+}
+
+// Close closes the database connection.
+func (a *Admin) Close() error {
+	return a.db.Close()
+}
+
+// ignoreOracleError returns nil if err is the given Oracle error, and err otherwise.
+func ignoreOracleError(err error, code string) error {
+	if leadingCode(err) == code {
+		return nil
+	}
+	return err
+}
+
+// nullString returns nil for an empty string, so that it is bound as NULL.
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullInt returns nil for zero, so that it is bound as NULL.
+func nullInt(i int) interface{} {
+	if i == 0 {
+		return nil
+	}
+	return i
+}
+
+// boolInt returns 1 for true and 0 for false, as PL/SQL booleans cannot be bound.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// seconds converts d to whole seconds, mapping negative durations to
+// DBMS_AQADM.INFINITE.
+func seconds(d time.Duration) int {
+	if d < 0 {
+		return -1
+	}
+	return int(d / time.Second)
+}
@@ -0,0 +1,174 @@
+package oraaq
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockAdmin returns an Admin over a sqlmock database.
+func newMockAdmin(t *testing.T) (*Admin, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err, "An error was not expected when opening a stub database connection")
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+	return NewAdmin(db), mock
+}
+
+// bindPlaceholder matches the positional placeholders of a statement.
+var bindPlaceholder = regexp.MustCompile(`:(\d+)`)
+
+// requireAscendingBinds fails unless the placeholders of query first appear in the
+// order :1, :2, ..., as go-ora binds positional arguments in order of appearance.
+func requireAscendingBinds(t *testing.T, name string, query string) {
+	t.Helper()
+	next := 1
+	for _, match := range bindPlaceholder.FindAllStringSubmatch(query, -1) {
+		n, err := strconv.Atoi(match[1])
+		require.NoError(t, err)
+		if n < next {
+			continue
+		}
+		require.Equal(t, next, n, "%s binds :%d before :%d", name, n, next)
+		next++
+	}
+}
+
+func TestAdminSQL_BindOrder(t *testing.T) {
+	for name, query := range map[string]string{
+		"createQueueTableSQL":    createQueueTableSQL,
+		"dropQueueTableSQL":      dropQueueTableSQL,
+		"createQueueSQL":         createQueueSQL,
+		"alterQueueSQL":          alterQueueSQL,
+		"startQueueSQL":          startQueueSQL,
+		"stopQueueSQL":           stopQueueSQL,
+		"dropQueueSQL":           dropQueueSQL,
+		"grantQueuePrivilegeSQL": grantQueuePrivilegeSQL,
+	} {
+		requireAscendingBinds(t, name, query)
+	}
+}
+
+func TestCreateQueueTable(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(createQueueTableSQL)).
+		WithArgs("msg_table", defaultPayloadType, 1, nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := admin.CreateQueueTable(context.Background(), "msg_table", QueueTableOptions{MultipleConsumers: true})
+	require.NoError(t, err)
+}
+
+func TestEnsureQueueTable_Exists(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(createQueueTableSQL)).
+		WithArgs("msg_table", "RAW", 0, "TABLESPACE USERS").
+		WillReturnError(errors.New("ORA-24001: cannot create QUEUE_TABLE, MSG_TABLE already exists"))
+
+	err := admin.EnsureQueueTable(context.Background(), "msg_table", QueueTableOptions{
+		PayloadType:   "RAW",
+		StorageClause: "TABLESPACE USERS",
+	})
+	require.NoError(t, err, "An existing queue table should not be an error")
+}
+
+func TestCreateQueue_Error(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(createQueueSQL)).
+		WithArgs("msg_queue", "msg_table", nil, 0, 0).
+		WillReturnError(errors.New("ORA-24006: cannot create QUEUE, MSG_QUEUE already exists"))
+
+	err := admin.CreateQueue(context.Background(), "msg_queue", "msg_table", QueueOptions{})
+	require.ErrorContains(t, err, "ORA-24006", "CreateQueue should not ignore an existing queue")
+}
+
+func TestEnsureQueue(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(createQueueTableSQL)).
+		WithArgs("msg_table", defaultPayloadType, 0, nil).
+		WillReturnError(errors.New("ORA-24001: cannot create QUEUE_TABLE, MSG_TABLE already exists"))
+	mock.ExpectExec(regexp.QuoteMeta(createQueueSQL)).
+		WithArgs("msg_queue", "msg_table", 3, 10, -1).
+		WillReturnError(errors.New("ORA-24006: cannot create QUEUE, MSG_QUEUE already exists"))
+	mock.ExpectExec(regexp.QuoteMeta(startQueueSQL)).
+		WithArgs("msg_queue").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := admin.EnsureQueue(context.Background(), "msg_queue", "msg_table", QueueOptions{
+		MaxRetries:    3,
+		RetryDelay:    10 * time.Second,
+		RetentionTime: -1,
+	})
+	require.NoError(t, err)
+}
+
+func TestEnsureQueue_Error(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(createQueueTableSQL)).
+		WithArgs("msg_table", defaultPayloadType, 0, nil).
+		WillReturnError(errors.New("ORA-01031: insufficient privileges"))
+
+	err := admin.EnsureQueue(context.Background(), "msg_queue", "msg_table", QueueOptions{})
+	require.ErrorContains(t, err, "ORA-01031")
+}
+
+func TestAlterQueue(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	// Zero options are bound as NULL to leave them unchanged
+	mock.ExpectExec(regexp.QuoteMeta(alterQueueSQL)).
+		WithArgs("msg_queue", nil, 60, nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := admin.AlterQueue(context.Background(), "msg_queue", QueueOptions{RetryDelay: time.Minute})
+	require.NoError(t, err)
+}
+
+func TestEnsureQueueDropped(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(stopQueueSQL)).
+		WithArgs("msg_queue").
+		WillReturnError(errors.New("ORA-24010: QUEUE MSG_QUEUE does not exist"))
+	mock.ExpectExec(regexp.QuoteMeta(dropQueueSQL)).
+		WithArgs("msg_queue").
+		WillReturnError(errors.New("ORA-24010: QUEUE MSG_QUEUE does not exist"))
+
+	err := admin.EnsureQueueDropped(context.Background(), "msg_queue")
+	require.NoError(t, err, "A missing queue should not be an error")
+}
+
+func TestEnsureQueueTableDropped(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(dropQueueTableSQL)).
+		WithArgs("msg_table", 1).
+		WillReturnError(errors.New("ORA-24002: QUEUE_TABLE MSG_TABLE does not exist"))
+
+	err := admin.EnsureQueueTableDropped(context.Background(), "msg_table")
+	require.NoError(t, err, "A missing queue table should not be an error")
+}
+
+func TestGrant(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(grantQueuePrivilegeSQL)).
+		WithArgs("DEQUEUE", "msg_queue", "consumer").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := admin.Grant(context.Background(), PrivilegeDequeue, "msg_queue", "consumer")
+	require.NoError(t, err)
+}
@@ -0,0 +1,148 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	go_ora "github.com/sijms/go-ora/v2"
+)
+
+// Browse returns a BrowseIterator over the messages in the queue, read in
+// DBMS_AQ.BROWSE mode so that they are left in the queue. The iterator holds
+// a dedicated connection, as Oracle keeps the browse position per session,
+// and must be closed to return it to the pool.
+func (d *Dequeuer) Browse(ctx context.Context) (api.BrowseIterator[Message], error) {
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", oracleError(err))
+	}
+
+	return &Browser{
+		conn:         conn,
+		queueName:    d.queueName,
+		consumerName: d.consumerName,
+		browseSql:    browseSQL,
+		first:        true,
+	}, nil
+}
+
+// Browser iterates over the messages in an Oracle Advanced Queue without
+// removing them. It starts at the first message and moves to the next one
+// on every call to Next.
+type Browser struct {
+
+	// conn is the connection whose session holds the browse position.
+	conn *sql.Conn
+
+	// The Oracle Advance Queue that the Browser reads.
+	queueName string
+
+	// consumerName is the subscriber whose messages the Browser reads. It is
+	// empty for queues with a single consumer.
+	consumerName string
+
+	browseSql string
+
+	// first is true until the first message has been read.
+	first bool
+
+	message Message
+	err     error
+	done    bool
+}
+
+// Next reads the next message in the queue. It returns false when the end of
+// the queue has been reached or an error occurred.
+func (b *Browser) Next(ctx context.Context) bool {
+
+	if b.done {
+		return false
+	}
+
+	firstMessage := 0
+	if b.first {
+		firstMessage = 1
+	}
+
+	var content go_ora.Clob
+	var msgID string
+	var correlation sql.NullString
+	var replyTo sql.NullString
+	var errMsg sql.NullString
+	var properties sql.NullString
+
+	_, err := b.conn.ExecContext(ctx, b.browseSql,
+		b.queueName,
+		firstMessage,
+		b.consumerName,
+		go_ora.Out{Dest: &content, Size: 300000},
+		go_ora.Out{Dest: &msgID, Size: 32},
+		go_ora.Out{Dest: &correlation, Size: 128},
+		go_ora.Out{Dest: &replyTo, Size: 128},
+		go_ora.Out{Dest: &errMsg, Size: 4000},
+		go_ora.Out{Dest: &properties, Size: 32767},
+	)
+	if err != nil {
+		b.done = true
+		b.err = oracleError(err)
+		return false
+	} else if (errMsg != sql.NullString{}) {
+		b.done = true
+
+		// A timeout signals that there are no more messages
+		err = oracleError(errors.New(errMsg.String))
+		if !errors.Is(err, api.ErrTimeout) {
+			b.err = fmt.Errorf("error occurred during browse: %w", err)
+		}
+		return false
+	}
+	b.first = false
+
+	// Decode hex string to byte slice
+	decoded, err := hex.DecodeString(msgID)
+	if err != nil {
+		b.done = true
+		b.err = fmt.Errorf("failed to decode msgID: %w", err)
+		return false
+	}
+
+	props, err := decodeProperties(properties.String)
+	if err != nil {
+		b.done = true
+		b.err = err
+		return false
+	}
+
+	var msgIDArray [16]byte
+	copy(msgIDArray[:], decoded)
+	b.message = Message{
+		ID:          msgIDArray,
+		Content:     content.String,
+		Correlation: correlation.String,
+		ReplyTo:     replyTo.String,
+		Queue:       b.queueName,
+		Properties:  props,
+	}
+
+	return true
+}
+
+// Message returns the message read by the last call to Next.
+func (b *Browser) Message() api.Message[Message] {
+	return &b.message
+}
+
+// Err returns the error, if any, that ended the iteration.
+func (b *Browser) Err() error {
+	return b.err
+}
+
+// Close returns the Browser's connection to the pool.
+func (b *Browser) Close() error {
+	b.done = true
+	return b.conn.Close()
+}
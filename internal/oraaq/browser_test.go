@@ -0,0 +1,68 @@
+package oraaq
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"time"
+)
+
+func (suite *DequeuerTestSuite) TestBrowse_ConnError() {
+
+	// Create a new mock database whose connections cannot be used
+	db, _, err := sqlmock.New()
+	require.NoError(suite.T(), err, "An error was not expected when opening a stub database connection")
+	require.NoError(suite.T(), db.Close())
+
+	dequeuer := NewDequeuer(db, "testQueue")
+	_, err = dequeuer.Browse(context.Background())
+
+	require.Error(suite.T(), err, "An error was expected when calling Browse because the database is closed")
+}
+
+func (suite *DequeuerTestSuite) TestBrowse() {
+	dequeuer := NewDequeuer(suite.db, "text_msg_queue")
+	enqueuer := NewEnqueuer(suite.db, "text_msg_queue")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, text := range []string{"first", "second"} {
+		err := enqueuer.Enqueue(ctx, &Message{Content: text})
+		suite.Require().NoError(err, "Failed to enqueue message")
+	}
+
+	// Browse both messages, twice, to ensure they are left in the queue
+	for i := 0; i < 2; i++ {
+		it, err := dequeuer.Browse(ctx)
+		suite.Require().NoError(err, "Failed to browse queue")
+
+		var texts []string
+		for it.Next(ctx) {
+			texts = append(texts, it.Message().Text())
+		}
+		suite.NoError(it.Err())
+		suite.NoError(it.Close())
+		suite.Equal([]string{"first", "second"}, texts)
+	}
+
+	// The browsed messages can still be dequeued
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message")
+	suite.Equal("first", deqMsg.Message().Text())
+	suite.NoError(deqMsg.Ack(ctx))
+}
+
+func (suite *DequeuerTestSuite) TestBrowseEmptyQueue() {
+	dequeuer := NewDequeuer(suite.db, "text_msg_queue")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	it, err := dequeuer.Browse(ctx)
+	suite.Require().NoError(err, "Failed to browse queue")
+	defer func() { suite.NoError(it.Close()) }()
+
+	suite.False(it.Next(ctx), "Browsing an empty queue should not return a message")
+	suite.NoError(it.Err(), "Reaching the end of the queue is not an error")
+}
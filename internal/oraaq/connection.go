@@ -0,0 +1,211 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Defaults of the ReconnectPolicy backoff.
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// ReconnectPolicy controls how an operation that failed because the connection to
+// the database was lost is retried. Between attempts the database is pinged,
+// backing off exponentially from InitialBackoff up to MaxBackoff, until it can be
+// reached again.
+type ReconnectPolicy struct {
+
+	// InitialBackoff is the wait before the first reconnection attempt. It
+	// defaults to 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between reconnection attempts. It defaults to 30s.
+	MaxBackoff time.Duration
+
+	// MaxAttempts is the number of reconnection attempts before the error is
+	// returned. Zero keeps trying until the context is done.
+	MaxAttempts int
+}
+
+// ConnectionOptions holds the reconnection policy and the callbacks of a Connection.
+type ConnectionOptions struct {
+
+	// Reconnect is the policy for retrying operations after the connection is
+	// lost. If nil, the error is returned straight away.
+	Reconnect *ReconnectPolicy
+
+	// OnConnect is called when the connection is restored after being lost.
+	OnConnect func()
+
+	// OnDisconnect is called with the error when the connection is lost.
+	OnDisconnect func(err error)
+
+	// Logger logs when the connection is lost and restored, and each failed
+	// reconnection attempt. If nil, nothing is logged.
+	Logger *slog.Logger
+}
+
+// Connection tracks whether the database can be reached, retrying operations
+// according to its ReconnectPolicy and calling its callbacks when the connection
+// is lost or restored. It is safe for concurrent use.
+type Connection struct {
+	db   *sql.DB
+	opts ConnectionOptions
+
+	mu   sync.Mutex
+	lost bool
+}
+
+// NewConnection returns a Connection over db, which is taken to be connected.
+func NewConnection(db *sql.DB, opts ConnectionOptions) *Connection {
+	return &Connection{
+		db:   db,
+		opts: opts,
+	}
+}
+
+// Health pings the database, returning api.ErrConnectionLost if it cannot be
+// reached.
+func (c *Connection) Health(ctx context.Context) error {
+
+	err := c.ping(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.disconnected(err)
+		}
+		return err
+	}
+
+	c.connected()
+	return nil
+}
+
+// do runs op, and if it fails because the connection was lost, waits for the
+// database to be reachable again and reruns it, as allowed by the ReconnectPolicy.
+func (c *Connection) do(ctx context.Context, op func() error) error {
+
+	err := op()
+	if !errors.Is(err, api.ErrConnectionLost) {
+		return err
+	}
+	c.disconnected(err)
+
+	policy := c.opts.Reconnect
+	if policy == nil {
+		return err
+	}
+
+	backoff := policy.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultInitialBackoff
+	}
+	maxBackoff := policy.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	for attempt := 1; policy.MaxAttempts == 0 || attempt <= policy.MaxAttempts; attempt++ {
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+
+		if pingErr := c.ping(ctx); pingErr != nil {
+			c.log(ctx, slog.LevelDebug, "reconnection attempt failed",
+				slog.Int("attempt", attempt), slog.String("error", pingErr.Error()))
+			continue
+		}
+		c.connected()
+
+		err = op()
+		if !errors.Is(err, api.ErrConnectionLost) {
+			return err
+		}
+		c.disconnected(err)
+	}
+
+	c.log(ctx, slog.LevelError, "gave up reconnecting to the database",
+		slog.Int("attempts", policy.MaxAttempts), slog.String("error", err.Error()))
+	return err
+}
+
+// ping checks that the database can be reached. Any failure other than the
+// context being done counts as a lost connection.
+func (c *Connection) ping(ctx context.Context) error {
+
+	err := c.db.PingContext(ctx)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	err = oracleError(err)
+	if errors.Is(err, api.ErrConnectionLost) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", api.ErrConnectionLost, err)
+}
+
+// disconnected records that the connection was lost, calling OnDisconnect if it
+// was connected before.
+func (c *Connection) disconnected(err error) {
+
+	c.mu.Lock()
+	wasLost := c.lost
+	c.lost = true
+	c.mu.Unlock()
+
+	if wasLost {
+		return
+	}
+	c.log(context.Background(), slog.LevelWarn, "database connection lost", slog.String("error", err.Error()))
+	if c.opts.OnDisconnect != nil {
+		c.opts.OnDisconnect(err)
+	}
+}
+
+// connected records that the connection is usable, calling OnConnect if it was
+// lost before.
+func (c *Connection) connected() {
+
+	c.mu.Lock()
+	wasLost := c.lost
+	c.lost = false
+	c.mu.Unlock()
+
+	if !wasLost {
+		return
+	}
+	c.log(context.Background(), slog.LevelInfo, "database connection restored")
+	if c.opts.OnConnect != nil {
+		c.opts.OnConnect()
+	}
+}
+
+// log logs a message to the Logger, if there is one.
+func (c *Connection) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.opts.Logger != nil {
+		c.opts.Logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
+// withQueue returns opts with the queue name added to the Logger.
+func (opts ConnectionOptions) withQueue(queueName string) ConnectionOptions {
+	if opts.Logger != nil {
+		opts.Logger = opts.Logger.With(slog.String("queue", queueName))
+	}
+	return opts
+}
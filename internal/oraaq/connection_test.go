@@ -0,0 +1,126 @@
+package oraaq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockConnection returns a Connection over a stub database that expects pings.
+func newMockConnection(t *testing.T, opts ConnectionOptions) (*Connection, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err, "An error was not expected when opening a stub database connection")
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+	return NewConnection(db, opts), mock
+}
+
+var errLost = errors.New("ORA-03113: end-of-file on communication channel")
+
+func TestConnection_Health(t *testing.T) {
+	var disconnects, connects int
+	conn, mock := newMockConnection(t, ConnectionOptions{
+		OnConnect:    func() { connects++ },
+		OnDisconnect: func(error) { disconnects++ },
+	})
+
+	mock.ExpectPing().WillReturnError(errLost)
+	mock.ExpectPing().WillReturnError(errLost)
+	mock.ExpectPing()
+
+	err := conn.Health(context.Background())
+	require.ErrorIs(t, err, api.ErrConnectionLost)
+	err = conn.Health(context.Background())
+	require.ErrorIs(t, err, api.ErrConnectionLost)
+	require.NoError(t, conn.Health(context.Background()))
+
+	require.Equal(t, 1, disconnects)
+	require.Equal(t, 1, connects)
+}
+
+func TestConnection_DoReconnects(t *testing.T) {
+	var disconnects, connects int
+	conn, mock := newMockConnection(t, ConnectionOptions{
+		Reconnect:    &ReconnectPolicy{InitialBackoff: time.Millisecond},
+		OnConnect:    func() { connects++ },
+		OnDisconnect: func(error) { disconnects++ },
+	})
+
+	mock.ExpectPing().WillReturnError(errLost)
+	mock.ExpectPing()
+
+	attempts := 0
+	err := conn.do(context.Background(), func() error {
+		attempts++
+		if attempts == 1 {
+			return oracleError(errLost)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+	require.Equal(t, 1, disconnects)
+	require.Equal(t, 1, connects)
+}
+
+func TestConnection_DoMaxAttempts(t *testing.T) {
+	conn, mock := newMockConnection(t, ConnectionOptions{
+		Reconnect: &ReconnectPolicy{InitialBackoff: time.Millisecond, MaxAttempts: 2},
+	})
+
+	mock.ExpectPing().WillReturnError(errLost)
+	mock.ExpectPing().WillReturnError(errLost)
+
+	err := conn.do(context.Background(), func() error {
+		return oracleError(errLost)
+	})
+	require.ErrorIs(t, err, api.ErrConnectionLost)
+}
+
+func TestConnection_DoWithoutReconnect(t *testing.T) {
+	conn, _ := newMockConnection(t, ConnectionOptions{})
+
+	attempts := 0
+	err := conn.do(context.Background(), func() error {
+		attempts++
+		return oracleError(errLost)
+	})
+	require.ErrorIs(t, err, api.ErrConnectionLost)
+	require.Equal(t, 1, attempts)
+}
+
+func TestConnection_DoOtherError(t *testing.T) {
+	conn, _ := newMockConnection(t, ConnectionOptions{
+		Reconnect: &ReconnectPolicy{InitialBackoff: time.Millisecond},
+	})
+
+	err := conn.do(context.Background(), func() error {
+		return oracleError(errors.New("ORA-25207: enqueue failed"))
+	})
+	require.ErrorIs(t, err, api.ErrQueueDisabled)
+}
+
+func TestConnection_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	conn, mock := newMockConnection(t, ConnectionOptions{Logger: logger}.withQueue("text_msg_queue"))
+
+	mock.ExpectPing().WillReturnError(errLost)
+	mock.ExpectPing()
+
+	require.Error(t, conn.Health(context.Background()))
+	require.NoError(t, conn.Health(context.Background()))
+
+	logged := buf.String()
+	require.Contains(t, logged, `level=WARN msg="database connection lost"`)
+	require.Contains(t, logged, `level=INFO msg="database connection restored" queue=text_msg_queue`)
+}
@@ -22,3 +22,9 @@ func (d *DequeueMessage) Ack(_ context.Context) error {
 func (d *DequeueMessage) NAck(_ context.Context) error {
 	return d.tx.Rollback()
 }
+
+// Tx returns the transaction the message was dequeued in, which Ack commits and NAck
+// rolls back, implementing api.Transactional.
+func (d *DequeueMessage) Tx() *sql.Tx {
+	return d.tx
+}
@@ -4,20 +4,36 @@ import (
 	"context"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/pgvanniekerk/ezQue/api"
 	go_ora "github.com/sijms/go-ora/v2"
-	"strings"
+)
+
+// Values of the dequeue wait option, in seconds.
+const (
+	waitForever = -1
+	noWait      = 0
 )
 
 func NewDequeuer(db *sql.DB, queueName string) *Dequeuer {
 	return &Dequeuer{
 		db:         db,
+		conn:       NewConnection(db, ConnectionOptions{}),
 		queueName:  queueName,
 		dequeueSql: dequeueSQL,
 	}
 }
 
+// NewSubscriberDequeuer returns a Dequeuer that dequeues as consumerName, a
+// subscriber of a queue with multiple consumers, such as a sharded
+// Transactional Event Queue. Every subscriber receives each message.
+func NewSubscriberDequeuer(db *sql.DB, queueName string, consumerName string) *Dequeuer {
+	d := NewDequeuer(db, queueName)
+	d.consumerName = consumerName
+	return d
+}
+
 // Dequeuer represents a type that dequeues messages from an Oracle Advanced Queue.
 // It contains a pointer to the SQL database connection and the name of the queue it is bound to.
 type Dequeuer struct {
@@ -26,9 +42,17 @@ type Dequeuer struct {
 	// as a connection pool by default, and is safe for concurrent use.
 	db *sql.DB
 
+	// conn tracks whether db can be reached, and retries dequeues after
+	// the connection is lost.
+	conn *Connection
+
 	// The Oracle Advance Queue that the Dequeuer is bound to.
 	queueName string
 
+	// consumerName is the subscriber the Dequeuer dequeues as. It is empty
+	// for queues with a single consumer.
+	consumerName string
+
 	dequeueSql string
 }
 
@@ -37,54 +61,103 @@ type Dequeuer struct {
 // The method begins a new transaction, executes the dequeue PL/SQL anonymous block, and
 // reads the message data from the result set. It then builds a DequeueMessage object with
 // the message data and the transaction. The result set is closed before returning the
-// DequeueMessage object.
+// DequeueMessage object. If the connection is lost, the dequeue is retried as allowed
+// by the ReconnectPolicy.
 func (d *Dequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+	return d.reconnecting(ctx, api.Selector{}, waitForever)
+}
+
+// DequeueWhere retrieves the first message matching the Selector, instead of whatever
+// message is next. The Selector maps onto the msgid, correlation and deq_condition
+// dequeue options, so Condition is a SQL predicate that may refer to the message
+// properties (such as priority) and to the payload as tab.user_data. As with Dequeue,
+// it waits until a matching message is available or the context is cancelled.
+func (d *Dequeuer) DequeueWhere(ctx context.Context, sel api.Selector) (api.DequeueMessage[Message], error) {
+	return d.reconnecting(ctx, sel, waitForever)
+}
+
+// reconnecting dequeues with the given selection, retrying the dequeue if the
+// connection is lost.
+func (d *Dequeuer) reconnecting(ctx context.Context, sel api.Selector, wait int) (api.DequeueMessage[Message], error) {
+
+	var deqMsg api.DequeueMessage[Message]
+	err := d.conn.do(ctx, func() error {
+		var err error
+		deqMsg, err = d.dequeue(ctx, sel, wait)
+		return err
+	})
+
+	return deqMsg, err
+}
+
+// dequeue executes the dequeue PL/SQL anonymous block with the given selection,
+// waiting up to wait seconds for a message.
+func (d *Dequeuer) dequeue(ctx context.Context, sel api.Selector, wait int) (api.DequeueMessage[Message], error) {
 
 	// Begin a new transaction that will be passed into the
 	// DequeueMessage object to allow Commit/Rollback.
 	tx, err := d.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, oracleError(err)
 	}
 
 	var content go_ora.Clob
 	var msgID string
+	var correlation sql.NullString
+	var replyTo sql.NullString
 	var errMsg sql.NullString
+	var properties sql.NullString
 
 	// Execute the dequeue PL/SQL anonymous block, waiting
 	// forever until a message is returned or until the context
 	// has been cancelled.
 	_, err = tx.ExecContext(ctx, d.dequeueSql,
 		d.queueName,
+		hex.EncodeToString(sel.MessageID),
+		sel.CorrelationID,
+		sel.Condition,
+		d.consumerName,
+		wait,
 		go_ora.Out{Dest: &content, Size: 300000},
 		go_ora.Out{Dest: &msgID, Size: 32},
+		go_ora.Out{Dest: &correlation, Size: 128},
+		go_ora.Out{Dest: &replyTo, Size: 128},
 		go_ora.Out{Dest: &errMsg, Size: 4000},
+		go_ora.Out{Dest: &properties, Size: 32767},
 	)
 	if err != nil {
+		_ = tx.Rollback()
 
-		// Check if the error is a 'cancel of current operation' from Oracle
-		if strings.Contains(err.Error(), "ORA-01013") {
-			// Wrap it as a context deadline exceeded
-			err = context.DeadlineExceeded
-		}
-
-		return nil, err
+		// A cancelled call is returned as a context deadline exceeded
+		return nil, oracleError(err)
 	} else if (errMsg != sql.NullString{}) {
-		return nil, fmt.Errorf("error occurred during dequeue: %s", errMsg.String)
+		_ = tx.Rollback()
+		return nil, fmt.Errorf("error occurred during dequeue: %w", oracleError(errors.New(errMsg.String)))
 	}
 
 	// Decode hex string to byte slice
 	decoded, err := hex.DecodeString(msgID)
 	if err != nil {
+		_ = tx.Rollback()
 		return nil, fmt.Errorf("failed to decode msgID: %w", err)
 	}
 
+	props, err := decodeProperties(properties.String)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Read the message data from the result set
 	var msgIDArray [16]byte
 	copy(msgIDArray[:], decoded)
 	message := Message{
-		ID:      msgIDArray,
-		Content: content.String,
+		ID:          msgIDArray,
+		Content:     content.String,
+		Correlation: correlation.String,
+		ReplyTo:     replyTo.String,
+		Queue:       d.queueName,
+		Properties:  props,
 	}
 
 	// Build DequeueMessage
@@ -96,6 +169,17 @@ func (d *Dequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], er
 	return deqMsg, nil
 }
 
+// SetConnectionOptions sets the reconnection policy and the callbacks used when
+// the connection to the database is lost or restored.
+func (d *Dequeuer) SetConnectionOptions(opts ConnectionOptions) {
+	d.conn = NewConnection(d.db, opts.withQueue(d.queueName))
+}
+
+// Health pings the database, returning api.ErrConnectionLost if it cannot be reached.
+func (d *Dequeuer) Health(ctx context.Context) error {
+	return d.conn.Health(ctx)
+}
+
 func (d *Dequeuer) Disconnect(_ context.Context) error {
 
 	err := d.db.Close()
@@ -104,7 +188,9 @@ func (d *Dequeuer) Disconnect(_ context.Context) error {
 	}
 
 	d.db = nil
+	d.conn = nil
 	d.dequeueSql = ""
 	d.queueName = ""
+	d.consumerName = ""
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pgvanniekerk/ezQue/api"
 	go_ora "github.com/sijms/go-ora/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -181,7 +182,7 @@ func (suite *DequeuerTestSuite) TestDequeueEmptyQueue() {
 	// The operation should return an error
 	suite.Error(err, "Expected an error when dequeuing from an empty queue")
 	// Assert that the error is a deadline exceeded error indicating a timeout
-	suite.EqualError(err, context.DeadlineExceeded.Error())
+	suite.ErrorIs(err, context.DeadlineExceeded)
 
 	if err != nil {
 		// If an error occurred, the returned DequeueMessage should be nil
@@ -320,5 +321,77 @@ func (suite *DequeuerTestSuite) TestDequeueWithMessageAndAck() {
 
 	// We expect a timeout error because the message has been acked and should no longer be in the queue.
 	suite.Error(err, "Expected error when trying to re-dequeue the message")
-	suite.Equal(err, context.DeadlineExceeded)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWhere() {
+	dequeuer := NewDequeuer(suite.db, "text_msg_queue")
+	enqueuer := NewEnqueuer(suite.db, "text_msg_queue")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, correlation := range []string{"first", "second", "third"} {
+		err := enqueuer.Enqueue(ctx, &Message{Content: correlation + " message", Correlation: correlation})
+		suite.Require().NoError(err, "Failed to enqueue message")
+	}
+
+	// Select by correlation, skipping the first message
+	deqMsg, err := dequeuer.DequeueWhere(ctx, api.Selector{CorrelationID: "second"})
+	suite.Require().NoError(err, "Failed to dequeue message by correlation")
+	suite.Equal("second message", deqMsg.Message().Text())
+	suite.Equal("second", deqMsg.Message().Raw().Correlation)
+	suite.NoError(deqMsg.Ack(ctx))
+
+	// Select by condition on the message properties
+	deqMsg, err = dequeuer.DequeueWhere(ctx, api.Selector{Condition: "tab.corrid = 'third'"})
+	suite.Require().NoError(err, "Failed to dequeue message by condition")
+	suite.Equal("third message", deqMsg.Message().Text())
+	suite.NoError(deqMsg.Ack(ctx))
+
+	// Browse for the remaining message's ID and select by it
+	it, err := dequeuer.Browse(ctx)
+	suite.Require().NoError(err, "Failed to browse queue")
+	suite.Require().True(it.Next(ctx), "Expected a remaining message")
+	msgID := it.Message().Raw().ID
+	suite.NoError(it.Close())
+
+	deqMsg, err = dequeuer.DequeueWhere(ctx, api.Selector{MessageID: msgID[:]})
+	suite.Require().NoError(err, "Failed to dequeue message by message id")
+	suite.Equal("first message", deqMsg.Message().Text())
+	suite.NoError(deqMsg.Ack(ctx))
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWhere_NoMatch() {
+	dequeuer := NewDequeuer(suite.db, "text_msg_queue")
+	enqueuer := NewEnqueuer(suite.db, "text_msg_queue")
+
+	err := enqueuer.Enqueue(context.Background(), &Message{Content: "test message", Correlation: "other"})
+	suite.Require().NoError(err, "Failed to enqueue message")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Waits for a matching message, which never arrives
+	_, err = dequeuer.DequeueWhere(ctx, api.Selector{CorrelationID: "reply"})
+	suite.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueReplyTo() {
+	dequeuer := NewDequeuer(suite.db, "text_msg_queue")
+	enqueuer := NewEnqueuer(suite.db, "text_msg_queue")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	msg := &Message{Content: "test message"}
+	msg.SetCorrelationID("request-1")
+	msg.SetReplyTo("reply_queue")
+	suite.Require().NoError(enqueuer.Enqueue(ctx, msg), "Failed to enqueue message")
+
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message")
+	suite.Equal("request-1", deqMsg.Message().Raw().Correlation)
+	suite.Equal("reply_queue", deqMsg.Message().Raw().ReplyTo)
+	suite.NoError(deqMsg.Ack(ctx))
 }
@@ -10,6 +10,7 @@ import (
 func NewEnqueuer(db *sql.DB, queueName string) *Enqueuer {
 	return &Enqueuer{
 		db:         db,
+		conn:       NewConnection(db, ConnectionOptions{}),
 		queueName:  queueName,
 		enqueueSql: enqueueSql,
 	}
@@ -21,10 +22,18 @@ type Enqueuer struct {
 	// as a connection pool by default, and is safe for concurrent use.
 	db *sql.DB
 
+	// conn tracks whether db can be reached, and retries enqueues after
+	// the connection is lost.
+	conn *Connection
+
 	// The Oracle Advance Queue that the Enqueuer is bound to.
 	queueName string
 
 	enqueueSql string
+
+	// idempotencyTable is the table the idempotency keys of enqueued messages are
+	// inserted into, or empty if idempotency keys are not supported.
+	idempotencyTable string
 }
 
 // NewMessage returns a new instance of `Message` that implements the `api.Message` interface.
@@ -37,35 +46,102 @@ func (e *Enqueuer) NewMessage() api.Message[Message] {
 // Enqueue enqueues a message to the Oracle Advanced Queue.
 // It starts a new transaction, performs SQL to enqueue the message using the provided context and
 // message content, and commits the transaction. If any error occurs during the process, it rolls back
-// the transaction and returns an error.
+// the transaction and returns an error. If the connection is lost, the enqueue is retried as
+// allowed by the ReconnectPolicy, so a message whose commit was lost may be enqueued twice,
+// unless it has an idempotency key and an idempotency table is set.
 func (e *Enqueuer) Enqueue(ctx context.Context, msg api.Message[Message]) error {
+	return e.conn.do(ctx, func() error {
+		return e.enqueue(ctx, msg)
+	})
+}
+
+// enqueue performs a single attempt at enqueueing msg.
+func (e *Enqueuer) enqueue(ctx context.Context, msg api.Message[Message]) error {
 
 	// Start a new transaction
 	tx, err := e.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+		return fmt.Errorf("failed to start transaction: %w", oracleError(err))
+	}
+
+	// Claim the idempotency key, in the same transaction as the enqueue
+	raw := msg.Raw()
+	if raw.IdempotencyKey != "" {
+		enqueued, err := e.claimKey(ctx, tx, raw.IdempotencyKey)
+		if err != nil || enqueued {
+			rollbackErr := tx.Rollback()
+			if err != nil && rollbackErr != nil {
+				return fmt.Errorf("%w, failed to rollback: %w", err, rollbackErr)
+			}
+			return err
+		}
 	}
 
 	// Perform SQL to enqueue message
-	_, err = tx.ExecContext(ctx, e.enqueueSql, e.queueName, msg.Text())
+	_, err = tx.ExecContext(ctx, e.enqueueSql, e.queueName, msg.Text(), raw.Correlation, raw.ReplyTo, encodeProperties(raw.Properties))
 	if err != nil {
 		// Rollback transaction in case of an error
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
-			return fmt.Errorf("enqueue failed: %v, failed to rollback: %w", err, rollbackErr)
+			return fmt.Errorf("enqueue failed: %w, failed to rollback: %w", oracleError(err), rollbackErr)
 		}
-		return fmt.Errorf("failed to enqueue message: %w", err)
+		return fmt.Errorf("failed to enqueue message: %w", oracleError(err))
 	}
 
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", oracleError(err))
 	}
 
 	return nil
 }
 
+// claimKey inserts key into the idempotency table in tx, returning true if it is
+// already there because a message with the same key was enqueued before.
+func (e *Enqueuer) claimKey(ctx context.Context, tx *sql.Tx, key string) (bool, error) {
+
+	if e.idempotencyTable == "" {
+		return false, fmt.Errorf("%w: no idempotency table is set for the idempotency key", api.ErrNotSupported)
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(InsertIdempotencyKeySQL, e.idempotencyTable), key)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to claim idempotency key: %w", oracleError(err))
+	}
+
+	return false, nil
+}
+
+// SetIdempotencyTable sets the table the idempotency keys of enqueued messages are
+// inserted into, in the same transaction as the enqueue, so that a message whose key
+// is already in the table is not enqueued again. The table, which may be qualified
+// with the schema, can be created with Admin.EnsureIdempotencyTable.
+func (e *Enqueuer) SetIdempotencyTable(table string) error {
+
+	table, err := IdempotencyTableName(table)
+	if err != nil {
+		return fmt.Errorf("oraaq: %w", err)
+	}
+
+	e.idempotencyTable = table
+	return nil
+}
+
+// SetConnectionOptions sets the reconnection policy and the callbacks used when
+// the connection to the database is lost or restored.
+func (e *Enqueuer) SetConnectionOptions(opts ConnectionOptions) {
+	e.conn = NewConnection(e.db, opts.withQueue(e.queueName))
+}
+
+// Health pings the database, returning api.ErrConnectionLost if it cannot be reached.
+func (e *Enqueuer) Health(ctx context.Context) error {
+	return e.conn.Health(ctx)
+}
+
 func (e *Enqueuer) Disconnect(_ context.Context) error {
 
 	err := e.db.Close()
@@ -74,7 +150,9 @@ func (e *Enqueuer) Disconnect(_ context.Context) error {
 	}
 
 	e.db = nil
+	e.conn = nil
 	e.enqueueSql = ""
 	e.queueName = ""
+	e.idempotencyTable = ""
 	return nil
 }
@@ -0,0 +1,98 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"regexp"
+)
+
+// oracleErrors maps Oracle error codes onto the api errors.
+var oracleErrors = map[string]error{
+	"ORA-25228": api.ErrTimeout,         // timeout or end-of-fetch during message dequeue
+	"ORA-25254": api.ErrTimeout,         // time-out in LISTEN while waiting for a message
+	"ORA-25207": api.ErrQueueDisabled,   // enqueue failed, queue is disabled from enqueueing
+	"ORA-25226": api.ErrQueueDisabled,   // dequeue failed, queue is not enabled for dequeue
+	"ORA-24010": api.ErrQueueNotFound,   // QUEUE does not exist
+	"ORA-25205": api.ErrQueueNotFound,   // the QUEUE does not exist
+	"ORA-01031": api.ErrUnauthorized,    // insufficient privileges
+	"ORA-01017": api.ErrUnauthorized,    // invalid username/password; logon denied
+	"ORA-03113": api.ErrConnectionLost,  // end-of-file on communication channel
+	"ORA-03114": api.ErrConnectionLost,  // not connected to ORACLE
+	"ORA-03135": api.ErrConnectionLost,  // connection lost contact
+	"ORA-01461": api.ErrPayloadTooLarge, // can bind a LONG value only for insert into a LONG column
+}
+
+// oracleCode matches an Oracle error code. The first code in a message is the error
+// itself; the codes after it, such as ORA-06512, locate it in the PL/SQL stack.
+var oracleCode = regexp.MustCompile(`ORA-\d{5}`)
+
+// leadingCode returns the leading Oracle error code of err, or an empty string if err
+// has none.
+func leadingCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	return oracleCode.FindString(err.Error())
+}
+
+// oracleError maps err onto the api error matching its leading Oracle error code,
+// keeping err wrapped. A cancelled call (ORA-01013) is mapped onto
+// context.DeadlineExceeded, and errors without a known code are returned as they are.
+func oracleError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Check if the error is a 'cancel of current operation' from Oracle
+	code := leadingCode(err)
+	if code == "ORA-01013" {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return fmt.Errorf("%w: %w", api.ErrConnectionLost, err)
+	}
+
+	if apiErr, ok := oracleErrors[code]; ok {
+		return fmt.Errorf("%w: %w", apiErr, err)
+	}
+
+	return err
+}
+
+// transientErrors are Oracle errors, besides those mapped onto api.ErrTimeout and
+// api.ErrConnectionLost, that may succeed if the operation is retried.
+var transientErrors = []string{
+	"ORA-00054", // resource busy and acquire with NOWAIT specified or timeout expired
+	"ORA-00060", // deadlock detected while waiting for resource
+	"ORA-04061", // existing state of package has been invalidated
+	"ORA-04068", // existing state of packages has been discarded
+	"ORA-12514", // listener does not currently know of service requested
+	"ORA-12541", // no listener
+}
+
+// Retryable reports whether err is a transient failure that may succeed if the
+// operation is retried: a timeout, a lost connection, a deadlock, a busy resource
+// or a package that was recompiled under the session.
+func Retryable(err error) bool {
+
+	if api.Retryable(err) {
+		return true
+	}
+
+	code := leadingCode(err)
+	if code == "" {
+		return false
+	}
+	for _, transient := range transientErrors {
+		if code == transient {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOracleError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"timeout", errors.New("ORA-25228: timeout or end-of-fetch during message dequeue from AQ.TEXT_MSG_QUEUE"), api.ErrTimeout},
+		{"listen timeout", errors.New("ORA-25254: time-out in LISTEN while waiting for a message"), api.ErrTimeout},
+		{"enqueue disabled", errors.New("ORA-25207: enqueue failed, queue AQ.TEXT_MSG_QUEUE is disabled from enqueueing"), api.ErrQueueDisabled},
+		{"dequeue disabled", errors.New("ORA-25226: dequeue failed, queue AQ.TEXT_MSG_QUEUE is not enabled for dequeue"), api.ErrQueueDisabled},
+		{"not found", errors.New("ORA-24010: QUEUE AQ.MISSING does not exist"), api.ErrQueueNotFound},
+		{"privileges", errors.New("ORA-01031: insufficient privileges"), api.ErrUnauthorized},
+		{"logon", errors.New("ORA-01017: invalid username/password; logon denied"), api.ErrUnauthorized},
+		{"end of file", errors.New("ORA-03113: end-of-file on communication channel"), api.ErrConnectionLost},
+		{"bad connection", driver.ErrBadConn, api.ErrConnectionLost},
+		{"too large", errors.New("ORA-01461: can bind a LONG value only for insert into a LONG column"), api.ErrPayloadTooLarge},
+		{"stack", errors.New("ORA-25228: timeout or end-of-fetch during message dequeue from AQ.TEXT_MSG_QUEUE\nORA-06512: at \"SYS.DBMS_AQ\", line 1143\nORA-01031: insufficient privileges"), api.ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := oracleError(tt.err)
+			require.ErrorIs(t, err, tt.want)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestOracleError_Cancelled(t *testing.T) {
+	orig := errors.New("ORA-01013: user requested cancel of current operation")
+	err := oracleError(orig)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, orig)
+}
+
+func TestOracleError_LeadingCode(t *testing.T) {
+	// Only the leading code classifies the error, whatever else the stack holds
+	orig := errors.New("ORA-06502: PL/SQL: numeric or value error\nORA-06512: at line 12\nORA-25228: timeout")
+	for i := 0; i < 20; i++ {
+		require.Equal(t, orig, oracleError(orig))
+	}
+}
+
+func TestOracleError_Unknown(t *testing.T) {
+	orig := errors.New("ORA-00942: table or view does not exist")
+	require.Equal(t, orig, oracleError(orig))
+	require.Nil(t, oracleError(nil))
+}
+
+func TestRetryable(t *testing.T) {
+	require.True(t, api.Retryable(oracleError(errors.New("ORA-03135: connection lost contact"))))
+	require.True(t, api.Retryable(oracleError(errors.New("ORA-25228: timeout"))))
+	require.False(t, api.Retryable(oracleError(errors.New("ORA-01031: insufficient privileges"))))
+}
+
+func TestOracleRetryable(t *testing.T) {
+	require.True(t, Retryable(oracleError(errors.New("ORA-03113: end-of-file on communication channel"))))
+	require.True(t, Retryable(errors.New("ORA-00060: deadlock detected while waiting for resource")))
+	require.True(t, Retryable(errors.New("ORA-04068: existing state of packages has been discarded")))
+	require.False(t, Retryable(errors.New("ORA-00942: table or view does not exist")))
+	require.False(t, Retryable(errors.New("ORA-00942: table or view does not exist\nORA-06512: at line 3\nORA-00060: deadlock detected")))
+	require.False(t, Retryable(nil))
+}
+
+func TestIgnoreOracleError(t *testing.T) {
+	require.NoError(t, ignoreOracleError(errors.New("failed to create queue table: ORA-24001: cannot create QUEUE_TABLE, AQ.T already exists"), errQueueTableExists))
+	require.Error(t, ignoreOracleError(errors.New("ORA-01031: insufficient privileges\nORA-24001: cannot create QUEUE_TABLE"), errQueueTableExists))
+	require.NoError(t, ignoreOracleError(nil, errQueueTableExists))
+}
@@ -0,0 +1,164 @@
+package oraaq
+
+import (
+	"context"
+	"fmt"
+)
+
+// defaultEventQueuePayloadType is DBMS_AQADM.JMS_TYPE, which stores the JMS
+// messages the Enqueuer and Dequeuer work with.
+const defaultEventQueuePayloadType = "JMS"
+
+// Oracle errors that the subscriber Ensure variants treat as already done.
+const (
+	errSubscriberExists   = "ORA-24034"
+	errSubscriberNotFound = "ORA-24035"
+)
+
+// EventQueueOptions holds the settings of a sharded Transactional Event Queue.
+// Zero values use the DBMS_AQADM defaults.
+type EventQueueOptions struct {
+
+	// MultipleConsumers makes the queue a publish/subscribe queue. Every
+	// subscriber added with AddSubscriber receives each message, and must
+	// dequeue as that subscriber.
+	MultipleConsumers bool
+
+	// MaxRetries is the number of times a message can be rolled back before
+	// it is moved to the exception queue.
+	MaxRetries int
+
+	// PayloadType is the type of the messages stored in the queue. It defaults
+	// to JMS, which ezQue enqueues and dequeues.
+	PayloadType string
+
+	// StorageClause is appended to the CREATE TABLE statement of the queue table.
+	StorageClause string
+
+	// Shards is the number of event streams the queue is split into.
+	Shards int
+
+	// KeyBasedOrdering enables key-based enqueue, which places messages with
+	// the same key, their correlation identifier, on the same shard so that
+	// they are dequeued in the order they were enqueued.
+	KeyBasedOrdering bool
+}
+
+// CreateEventQueue creates a sharded Transactional Event Queue, which also
+// creates its queue table. The queue must be started with StartQueue before it
+// can be used.
+func (a *Admin) CreateEventQueue(ctx context.Context, queue string, opts EventQueueOptions) error {
+
+	payloadType := opts.PayloadType
+	if payloadType == "" {
+		payloadType = defaultEventQueuePayloadType
+	}
+
+	_, err := a.db.ExecContext(ctx, createEventQueueSQL,
+		queue,
+		boolInt(opts.MultipleConsumers),
+		nullInt(opts.MaxRetries),
+		payloadType,
+		nullString(opts.StorageClause),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create event queue %s: %w", queue, err)
+	}
+
+	if opts.Shards > 0 {
+		err = a.setQueueParameter(ctx, queue, "SHARD_NUM", opts.Shards)
+		if err != nil {
+			return err
+		}
+	}
+	if opts.KeyBasedOrdering {
+		err = a.setQueueParameter(ctx, queue, "KEY_BASED_ENQUEUE", 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnsureEventQueue creates a sharded Transactional Event Queue if it does not
+// exist yet, and starts it. The options of an existing queue are left as they are.
+func (a *Admin) EnsureEventQueue(ctx context.Context, queue string, opts EventQueueOptions) error {
+
+	err := ignoreOracleError(a.CreateEventQueue(ctx, queue, opts), errQueueExists)
+	if err != nil {
+		return err
+	}
+
+	return a.StartQueue(ctx, queue)
+}
+
+// DropEventQueue drops a sharded Transactional Event Queue along with its
+// queue table. The queue must have been stopped.
+func (a *Admin) DropEventQueue(ctx context.Context, queue string) error {
+
+	_, err := a.db.ExecContext(ctx, dropEventQueueSQL, queue)
+	if err != nil {
+		return fmt.Errorf("failed to drop event queue %s: %w", queue, err)
+	}
+
+	return nil
+}
+
+// EnsureEventQueueDropped stops and drops a sharded Transactional Event Queue
+// if it exists.
+func (a *Admin) EnsureEventQueueDropped(ctx context.Context, queue string) error {
+
+	err := ignoreOracleError(a.StopQueue(ctx, queue), errQueueNotFound)
+	if err != nil {
+		return err
+	}
+
+	return ignoreOracleError(a.DropEventQueue(ctx, queue), errQueueNotFound)
+}
+
+// AddSubscriber subscribes a consumer to a queue with multiple consumers.
+func (a *Admin) AddSubscriber(ctx context.Context, queue string, subscriber string) error {
+
+	_, err := a.db.ExecContext(ctx, addSubscriberSQL, queue, subscriber)
+	if err != nil {
+		return fmt.Errorf("failed to add subscriber %s to queue %s: %w", subscriber, queue, err)
+	}
+
+	return nil
+}
+
+// EnsureSubscriber subscribes a consumer to a queue with multiple consumers if
+// it is not subscribed yet.
+func (a *Admin) EnsureSubscriber(ctx context.Context, queue string, subscriber string) error {
+	return ignoreOracleError(a.AddSubscriber(ctx, queue, subscriber), errSubscriberExists)
+}
+
+// RemoveSubscriber unsubscribes a consumer from a queue with multiple consumers.
+// Messages that were only waiting for the subscriber are removed.
+func (a *Admin) RemoveSubscriber(ctx context.Context, queue string, subscriber string) error {
+
+	_, err := a.db.ExecContext(ctx, removeSubscriberSQL, queue, subscriber)
+	if err != nil {
+		return fmt.Errorf("failed to remove subscriber %s from queue %s: %w", subscriber, queue, err)
+	}
+
+	return nil
+}
+
+// EnsureSubscriberRemoved unsubscribes a consumer from a queue with multiple
+// consumers if it is subscribed.
+func (a *Admin) EnsureSubscriberRemoved(ctx context.Context, queue string, subscriber string) error {
+	return ignoreOracleError(a.RemoveSubscriber(ctx, queue, subscriber), errSubscriberNotFound)
+}
+
+// setQueueParameter sets a parameter of a sharded Transactional Event Queue.
+func (a *Admin) setQueueParameter(ctx context.Context, queue string, name string, value int) error {
+
+	_, err := a.db.ExecContext(ctx, setQueueParameterSQL, queue, name, value)
+	if err != nil {
+		return fmt.Errorf("failed to set %s on queue %s: %w", name, queue, err)
+	}
+
+	return nil
+}
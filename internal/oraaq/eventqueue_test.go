@@ -0,0 +1,102 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventQueueSQL_BindOrder(t *testing.T) {
+	for name, query := range map[string]string{
+		"createEventQueueSQL":  createEventQueueSQL,
+		"dropEventQueueSQL":    dropEventQueueSQL,
+		"setQueueParameterSQL": setQueueParameterSQL,
+		"addSubscriberSQL":     addSubscriberSQL,
+		"removeSubscriberSQL":  removeSubscriberSQL,
+	} {
+		requireAscendingBinds(t, name, query)
+	}
+}
+
+func TestCreateEventQueue(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(createEventQueueSQL)).
+		WithArgs("events", 1, 5, defaultEventQueuePayloadType, nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(setQueueParameterSQL)).
+		WithArgs("events", "SHARD_NUM", 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(setQueueParameterSQL)).
+		WithArgs("events", "KEY_BASED_ENQUEUE", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := admin.CreateEventQueue(context.Background(), "events", EventQueueOptions{
+		MultipleConsumers: true,
+		MaxRetries:        5,
+		Shards:            4,
+		KeyBasedOrdering:  true,
+	})
+	require.NoError(t, err)
+}
+
+func TestEnsureEventQueue_Exists(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	// The parameters of an existing queue are left as they are
+	mock.ExpectExec(regexp.QuoteMeta(createEventQueueSQL)).
+		WithArgs("events", 0, nil, "RAW", nil).
+		WillReturnError(errors.New("ORA-24006: cannot create QUEUE, EVENTS already exists"))
+	mock.ExpectExec(regexp.QuoteMeta(startQueueSQL)).
+		WithArgs("events").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := admin.EnsureEventQueue(context.Background(), "events", EventQueueOptions{
+		PayloadType: "RAW",
+		Shards:      4,
+	})
+	require.NoError(t, err)
+}
+
+func TestEnsureEventQueueDropped(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(stopQueueSQL)).
+		WithArgs("events").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(dropEventQueueSQL)).
+		WithArgs("events").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := admin.EnsureEventQueueDropped(context.Background(), "events")
+	require.NoError(t, err)
+}
+
+func TestEnsureSubscriber(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(addSubscriberSQL)).
+		WithArgs("events", "billing").
+		WillReturnError(errors.New("ORA-24034: application BILLING is already a subscriber for queue EVENTS"))
+	mock.ExpectExec(regexp.QuoteMeta(removeSubscriberSQL)).
+		WithArgs("events", "audit").
+		WillReturnError(errors.New("ORA-24035: AQ agent AUDIT is not a subscriber for queue EVENTS"))
+
+	require.NoError(t, admin.EnsureSubscriber(context.Background(), "events", "billing"))
+	require.NoError(t, admin.EnsureSubscriberRemoved(context.Background(), "events", "audit"))
+}
+
+func TestNewSubscriberDequeuer(t *testing.T) {
+	db := &sql.DB{}
+
+	dequeuer := NewSubscriberDequeuer(db, "events", "billing")
+
+	require.Equal(t, db, dequeuer.db, "The dequeuer's db property does not match the expected db")
+	require.Equal(t, "events", dequeuer.queueName, "The dequeuer's queueName property does not match the expected queue name")
+	require.Equal(t, "billing", dequeuer.consumerName, "The dequeuer's consumerName property does not match the expected consumer name")
+}
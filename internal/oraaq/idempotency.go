@@ -0,0 +1,27 @@
+package oraaq
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tableName matches the idempotency table names that are accepted, which may be
+// qualified with the schema.
+var tableName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*(\.[A-Za-z][A-Za-z0-9_$#]*)?$`)
+
+// IdempotencyTableName validates table, which is formatted into SQL statements, and
+// returns it in upper case. The tables of dedup.SQLStore have the same layout as
+// idempotency tables, and their names are validated with it too.
+func IdempotencyTableName(table string) (string, error) {
+	if !tableName.MatchString(table) {
+		return "", fmt.Errorf("invalid idempotency table name %q", table)
+	}
+	return strings.ToUpper(table), nil
+}
+
+// IsUniqueViolation reports whether err is led by ORA-00001, which is raised when a
+// key that is already in an idempotency table is inserted again.
+func IsUniqueViolation(err error) bool {
+	return leadingCode(err) == errUniqueViolated
+}
@@ -0,0 +1,116 @@
+package oraaq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockEnqueuer returns an Enqueuer over a sqlmock database, inserting idempotency
+// keys into the table IDEMPOTENCY_KEYS.
+func newMockEnqueuer(t *testing.T) (*Enqueuer, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err, "An error was not expected when opening a stub database connection")
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+	enq := NewEnqueuer(db, "text_msg_queue")
+	require.NoError(t, enq.SetIdempotencyTable("idempotency_keys"))
+	return enq, mock
+}
+
+var claimKeyQuery = regexp.QuoteMeta(fmt.Sprintf(InsertIdempotencyKeySQL, "IDEMPOTENCY_KEYS"))
+
+func TestEnqueue_IdempotencyKey(t *testing.T) {
+	enq, mock := newMockEnqueuer(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(claimKeyQuery).WithArgs("order-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(enqueueSql)).
+		WithArgs("text_msg_queue", "a", "", "", "").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := enq.Enqueue(context.Background(), &Message{Content: "a", IdempotencyKey: "order-1"})
+	require.NoError(t, err)
+}
+
+func TestEnqueue_IdempotencyKeyDuplicate(t *testing.T) {
+	enq, mock := newMockEnqueuer(t)
+
+	// the message is not enqueued again
+	mock.ExpectBegin()
+	mock.ExpectExec(claimKeyQuery).WithArgs("order-1").
+		WillReturnError(errors.New("ORA-00001: unique constraint (AQ.SYS_C008) violated"))
+	mock.ExpectRollback()
+
+	err := enq.Enqueue(context.Background(), &Message{Content: "a", IdempotencyKey: "order-1"})
+	require.NoError(t, err)
+}
+
+func TestEnqueue_IdempotencyKeyWithoutTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	enq := NewEnqueuer(db, "text_msg_queue")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = enq.Enqueue(context.Background(), &Message{Content: "a", IdempotencyKey: "order-1"})
+	require.ErrorIs(t, err, api.ErrNotSupported)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetIdempotencyTable_Invalid(t *testing.T) {
+	enq := NewEnqueuer(nil, "text_msg_queue")
+	require.Error(t, enq.SetIdempotencyTable("keys; DROP TABLE x"))
+	require.NoError(t, enq.SetIdempotencyTable("aq.idempotency_keys"))
+}
+
+func TestEnsureIdempotencyTable_Exists(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(IdempotencyTableSQL, "AQ.IDEMPOTENCY_KEYS"))).
+		WillReturnError(errors.New("ORA-00955: name is already used by an existing object"))
+
+	err := admin.EnsureIdempotencyTable(context.Background(), "aq.idempotency_keys")
+	require.NoError(t, err)
+}
+
+func TestEnqueue_IdempotencyKeyOtherError(t *testing.T) {
+	enq, mock := newMockEnqueuer(t)
+
+	// ORA-00001 raised further down the stack, by a trigger, is not a duplicate key
+	mock.ExpectBegin()
+	mock.ExpectExec(claimKeyQuery).WithArgs("order-1").
+		WillReturnError(errors.New("ORA-04088: error during execution of trigger 'AQ.AUDIT'\nORA-00001: unique constraint (AQ.SYS_C009) violated"))
+	mock.ExpectRollback()
+
+	err := enq.Enqueue(context.Background(), &Message{Content: "a", IdempotencyKey: "order-1"})
+	require.Error(t, err)
+}
+
+func TestDeleteIdempotencyKeysBefore(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+	before := time.Now().Add(-24 * time.Hour)
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(DeleteIdempotencyKeysBeforeSQL, "AQ.IDEMPOTENCY_KEYS"))).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := admin.DeleteIdempotencyKeysBefore(context.Background(), "aq.idempotency_keys", before)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+
+	_, err = admin.DeleteIdempotencyKeysBefore(context.Background(), "keys; DROP TABLE x", before)
+	require.Error(t, err)
+}
@@ -1,8 +1,36 @@
 package oraaq
 
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
 type Message struct {
 	ID      [16]byte
 	Content string
+
+	// Correlation is the correlation identifier of the message, which can be
+	// used to dequeue it selectively.
+	Correlation string
+
+	// ReplyTo is the name of the queue that a reply to the message should be
+	// enqueued to.
+	ReplyTo string
+
+	// Queue is the name of the queue the message was dequeued from. It is
+	// ignored on Enqueue.
+	Queue string
+
+	// Properties are the JMS string properties of the message, such as the
+	// W3C traceparent of the trace it was enqueued in.
+	Properties map[string]string
+
+	// IdempotencyKey identifies the message to the Enqueuer's idempotency
+	// table, so that it is enqueued at most once. It is ignored on Dequeue.
+	IdempotencyKey string
 }
 
 func (m *Message) Raw() Message {
@@ -16,8 +44,121 @@ func (m *Message) Text() string {
 func (m *Message) SetRaw(raw Message) {
 	m.ID = raw.ID
 	m.Content = raw.Content
+	m.Correlation = raw.Correlation
+	m.ReplyTo = raw.ReplyTo
+	m.Queue = raw.Queue
+	m.Properties = raw.Properties
+	m.IdempotencyKey = raw.IdempotencyKey
 }
 
 func (m *Message) SetText(msg string) {
 	m.Content = msg
 }
+
+// GetCorrelationID returns the correlation identifier, implementing api.Correlated.
+func (m *Message) GetCorrelationID() string {
+	return m.Correlation
+}
+
+// SetCorrelationID sets the correlation identifier, implementing api.Correlated.
+func (m *Message) SetCorrelationID(id string) {
+	m.Correlation = id
+}
+
+// GetReplyTo returns the reply-to queue name, implementing api.Correlated.
+func (m *Message) GetReplyTo() string {
+	return m.ReplyTo
+}
+
+// SetReplyTo sets the reply-to queue name, implementing api.Correlated.
+func (m *Message) SetReplyTo(replyTo string) {
+	m.ReplyTo = replyTo
+}
+
+// GetIdempotencyKey returns the idempotency key, implementing api.Idempotent.
+func (m *Message) GetIdempotencyKey() string {
+	return m.IdempotencyKey
+}
+
+// SetIdempotencyKey sets the idempotency key, implementing api.Idempotent.
+func (m *Message) SetIdempotencyKey(key string) {
+	m.IdempotencyKey = key
+}
+
+// GetProperty returns the named property, implementing api.Properties.
+func (m *Message) GetProperty(key string) string {
+	return m.Properties[key]
+}
+
+// SetProperty sets the named property, implementing api.Properties.
+func (m *Message) SetProperty(key string, value string) {
+	if m.Properties == nil {
+		m.Properties = make(map[string]string)
+	}
+	m.Properties[key] = value
+}
+
+// PropertyKeys returns the names of the properties, implementing api.Properties.
+func (m *Message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.Properties))
+	for key := range m.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeProperties URL encodes properties as name=value pairs separated by &, as
+// they are passed to and from PL/SQL, where they are decoded with UTL_URL.
+func encodeProperties(properties map[string]string) string {
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, escapeProperty(key)+"="+escapeProperty(properties[key]))
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// escapeProperty URL encodes s, encoding spaces as %20 since UTL_URL.UNESCAPE
+// does not decode +.
+func escapeProperty(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// decodeProperties decodes properties encoded by encodeProperties or by the
+// dequeue PL/SQL. It returns nil if there are none.
+func decodeProperties(encoded string) (map[string]string, error) {
+
+	if encoded == "" {
+		return nil, nil
+	}
+
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode message properties: %w", err)
+	}
+
+	properties := make(map[string]string, len(values))
+	for key, value := range values {
+		properties[key] = value[0]
+	}
+
+	return properties, nil
+}
+
+// GetMessageID returns the message ID in hexadecimal, as RAWTOHEX formats it, or an
+// empty string if the message has not been enqueued or dequeued. It implements
+// api.Identified.
+func (m *Message) GetMessageID() string {
+	if m.ID == ([16]byte{}) {
+		return ""
+	}
+	return strings.ToUpper(hex.EncodeToString(m.ID[:]))
+}
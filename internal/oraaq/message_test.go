@@ -11,8 +11,10 @@ func TestRaw(t *testing.T) {
 	var id [16]byte
 
 	message := &Message{
-		ID:      id,
-		Content: content,
+		ID:          id,
+		Content:     content,
+		Correlation: "testCorrelation",
+		Queue:       "testQueue",
 	}
 
 	raw := message.Raw()
@@ -20,6 +22,8 @@ func TestRaw(t *testing.T) {
 	// check if the ID and Content properties of the raw message match those of the original message
 	require.Equal(t, message.ID, raw.ID, "Raw ID does not match the original message's ID")
 	require.Equal(t, message.Content, raw.Content, "Raw Content does not match the original message's Content")
+	require.Equal(t, message.Correlation, raw.Correlation, "Raw Correlation does not match the original message's Correlation")
+	require.Equal(t, message.Queue, raw.Queue, "Raw Queue does not match the original message's Queue")
 }
 
 func TestText(t *testing.T) {
@@ -32,3 +36,43 @@ func TestText(t *testing.T) {
 	// Text() should return the Content property of the message
 	require.Equal(t, content, message.Text(), "Text does not return the correct message Content")
 }
+
+func TestProperties(t *testing.T) {
+	message := &Message{}
+	message.SetProperty("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	message.SetProperty("note", "a b&c=d+e")
+
+	require.Equal(t, "a b&c=d+e", message.GetProperty("note"))
+	require.Equal(t, []string{"note", "traceparent"}, message.PropertyKeys())
+
+	encoded := encodeProperties(message.Properties)
+	require.Equal(t, "note=a%20b%26c%3Dd%2Be&traceparent=00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", encoded)
+
+	decoded, err := decodeProperties(encoded)
+	require.NoError(t, err)
+	require.Equal(t, message.Properties, decoded)
+}
+
+func TestDecodeProperties_Empty(t *testing.T) {
+	decoded, err := decodeProperties("")
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+}
+
+func TestGetMessageID(t *testing.T) {
+	require.Equal(t, "", (&Message{}).GetMessageID())
+
+	message := &Message{ID: [16]byte{0xab, 0x01}}
+	require.Equal(t, "AB010000000000000000000000000000", message.GetMessageID())
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	message := &Message{}
+	message.SetIdempotencyKey("order-1")
+	require.Equal(t, "order-1", message.GetIdempotencyKey())
+
+	// SetRaw should copy the idempotency key
+	copied := &Message{}
+	copied.SetRaw(message.Raw())
+	require.Equal(t, "order-1", copied.IdempotencyKey)
+}
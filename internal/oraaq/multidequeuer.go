@@ -0,0 +1,169 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	go_ora "github.com/sijms/go-ora/v2"
+	"strings"
+)
+
+// NewMultiDequeuer returns a MultiDequeuer over the given queues. consumerName is
+// the subscriber to dequeue as, and is empty for queues with a single consumer.
+func NewMultiDequeuer(db *sql.DB, queueNames []string, consumerName string) *MultiDequeuer {
+	dequeuers := make([]*Dequeuer, 0, len(queueNames))
+	for _, queueName := range queueNames {
+		dequeuers = append(dequeuers, NewSubscriberDequeuer(db, queueName, consumerName))
+	}
+
+	return &MultiDequeuer{
+		db:           db,
+		conn:         NewConnection(db, ConnectionOptions{}),
+		dequeuers:    dequeuers,
+		consumerName: consumerName,
+		listenSql:    listenSQL,
+	}
+}
+
+// MultiDequeuer dequeues messages from several Oracle Advanced Queues while
+// holding a single waiting session. Rather than blocking a session in every
+// queue, it waits on all of them at once with DBMS_AQ.LISTEN, and only dequeues
+// from a queue once it has a message ready.
+type MultiDequeuer struct {
+
+	// db is a pointer to the SQL database connection. Note, this acts
+	// as a connection pool by default, and is safe for concurrent use.
+	db *sql.DB
+
+	// conn tracks whether db can be reached, and retries dequeues after
+	// the connection is lost.
+	conn *Connection
+
+	// dequeuers holds a Dequeuer for each queue, sharing db.
+	dequeuers []*Dequeuer
+
+	// consumerName is the subscriber the MultiDequeuer dequeues as. It is
+	// empty for queues with a single consumer.
+	consumerName string
+
+	listenSql string
+}
+
+// Dequeue waits until one of the queues has a message ready and dequeues it. The
+// Queue field of the returned message names the queue it was dequeued from. If
+// another consumer takes the message first, Dequeue goes back to waiting. It waits
+// indefinitely until a message is returned or until the context is cancelled. If the
+// connection is lost, it is retried as allowed by the ReconnectPolicy.
+func (m *MultiDequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	var deqMsg api.DequeueMessage[Message]
+	err := m.conn.do(ctx, func() error {
+		var err error
+		deqMsg, err = m.dequeue(ctx)
+		return err
+	})
+
+	return deqMsg, err
+}
+
+// dequeue performs a single attempt at waiting for and dequeueing a message.
+func (m *MultiDequeuer) dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	for {
+		dequeuer, err := m.listen(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		deqMsg, err := dequeuer.dequeue(ctx, api.Selector{}, noWait)
+		if errors.Is(err, api.ErrTimeout) {
+			// The message was taken by another consumer
+			continue
+		}
+
+		return deqMsg, err
+	}
+}
+
+// listen waits until one of the queues has a message ready, and returns its Dequeuer.
+func (m *MultiDequeuer) listen(ctx context.Context) (*Dequeuer, error) {
+
+	queueNames := make([]string, 0, len(m.dequeuers))
+	for _, dequeuer := range m.dequeuers {
+		queueNames = append(queueNames, dequeuer.queueName)
+	}
+
+	var address sql.NullString
+	var errMsg sql.NullString
+
+	// Execute the listen PL/SQL anonymous block, waiting
+	// forever until a message is ready or until the context
+	// has been cancelled.
+	_, err := m.db.ExecContext(ctx, m.listenSql,
+		strings.Join(queueNames, ","),
+		m.consumerName,
+		go_ora.Out{Dest: &address, Size: 1024},
+		go_ora.Out{Dest: &errMsg, Size: 4000},
+	)
+	if err != nil {
+
+		// A cancelled call is returned as a context deadline exceeded
+		return nil, oracleError(err)
+	} else if (errMsg != sql.NullString{}) {
+		return nil, fmt.Errorf("error occurred during listen: %w", oracleError(errors.New(errMsg.String)))
+	}
+
+	dequeuer := m.dequeuerFor(address.String)
+	if dequeuer == nil {
+		return nil, fmt.Errorf("listen returned unknown queue: %s", address.String)
+	}
+
+	return dequeuer, nil
+}
+
+// dequeuerFor returns the Dequeuer of the queue LISTEN returned the address of.
+// The address is in upper case and may be qualified with the schema.
+func (m *MultiDequeuer) dequeuerFor(address string) *Dequeuer {
+
+	address = strings.ReplaceAll(strings.ToUpper(address), `"`, "")
+	for _, dequeuer := range m.dequeuers {
+		queueName := strings.ToUpper(dequeuer.queueName)
+		if address == queueName || strings.HasSuffix(address, "."+queueName) {
+			return dequeuer
+		}
+	}
+
+	return nil
+}
+
+// SetConnectionOptions sets the reconnection policy and the callbacks used when
+// the connection to the database is lost or restored.
+func (m *MultiDequeuer) SetConnectionOptions(opts ConnectionOptions) {
+	queueNames := make([]string, 0, len(m.dequeuers))
+	for _, dequeuer := range m.dequeuers {
+		queueNames = append(queueNames, dequeuer.queueName)
+	}
+	m.conn = NewConnection(m.db, opts.withQueue(strings.Join(queueNames, ",")))
+}
+
+// Health pings the database, returning api.ErrConnectionLost if it cannot be reached.
+func (m *MultiDequeuer) Health(ctx context.Context) error {
+	return m.conn.Health(ctx)
+}
+
+func (m *MultiDequeuer) Disconnect(_ context.Context) error {
+
+	err := m.db.Close()
+	if err != nil {
+		return err
+	}
+
+	m.db = nil
+	m.conn = nil
+	m.dequeuers = nil
+	m.consumerName = ""
+	m.listenSql = ""
+	return nil
+}
@@ -0,0 +1,64 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultiDequeuer(t *testing.T) {
+	db := &sql.DB{}
+
+	dequeuer := NewMultiDequeuer(db, []string{"first_queue", "second_queue"}, "consumer")
+
+	require.Equal(t, db, dequeuer.db, "The dequeuer's db property does not match the expected db")
+	require.Len(t, dequeuer.dequeuers, 2, "There should be a Dequeuer for each queue")
+	require.Equal(t, "second_queue", dequeuer.dequeuers[1].queueName)
+	require.Equal(t, "consumer", dequeuer.dequeuers[1].consumerName)
+}
+
+func TestDequeuerFor(t *testing.T) {
+	dequeuer := NewMultiDequeuer(&sql.DB{}, []string{"first_queue", "second_queue"}, "")
+
+	require.Equal(t, "second_queue", dequeuer.dequeuerFor("SECOND_QUEUE").queueName)
+	require.Equal(t, "first_queue", dequeuer.dequeuerFor(`"AQ"."FIRST_QUEUE"`).queueName, "Schema qualified addresses should match")
+	require.Nil(t, dequeuer.dequeuerFor("AQ.OTHER_QUEUE"))
+}
+
+func (suite *DequeuerTestSuite) TestMultiDequeue() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Create a second queue in the test queue table
+	admin := NewAdmin(suite.db)
+	suite.Require().NoError(admin.EnsureQueue(ctx, "text_msg_queue_2", "text_msg_queue_table", QueueOptions{}))
+	defer func() { suite.NoError(admin.EnsureQueueDropped(context.Background(), "text_msg_queue_2")) }()
+
+	dequeuer := NewMultiDequeuer(suite.db, []string{"text_msg_queue", "text_msg_queue_2"}, "")
+	enqueuer := NewEnqueuer(suite.db, "text_msg_queue_2")
+
+	// Wait on both queues before the message arrives
+	go func() {
+		time.Sleep(time.Second)
+		suite.NoError(enqueuer.Enqueue(ctx, &Message{Content: "test message"}))
+	}()
+
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to dequeue message")
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.Equal("text_msg_queue_2", deqMsg.Message().Raw().Queue)
+	suite.NoError(deqMsg.Ack(ctx))
+}
+
+func (suite *DequeuerTestSuite) TestMultiDequeueCancelled() {
+	dequeuer := NewMultiDequeuer(suite.db, []string{"text_msg_queue"}, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := dequeuer.Dequeue(ctx)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+}
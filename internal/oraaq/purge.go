@@ -0,0 +1,59 @@
+package oraaq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PurgeExpired is a Purge condition that selects the messages that expired or
+// ran out of retries, and were moved to the exception queue.
+const PurgeExpired = "qtview.msg_state = 'EXPIRED'"
+
+// Purge removes messages of queue from its queue table with
+// DBMS_AQADM.PURGE_QUEUE_TABLE, and returns the number of messages purged.
+// This includes the messages of queue that were moved to the exception queue.
+//
+// condition is an optional SQL predicate on the columns of the AQ$ view of the
+// queue table, referred to as qtview, such as PurgeExpired or
+// "qtview.corr_id = 'x'". An empty condition purges all the messages of queue.
+// It is added to the purge condition as is, so it must not come from untrusted
+// input.
+//
+// The number purged is counted just before purging, so it can be off when
+// messages are enqueued or dequeued during the purge.
+func (a *Admin) Purge(ctx context.Context, queue string, condition string) (int64, error) {
+
+	owner, name := splitQueueName(queue)
+	var queueTable string
+	err := a.db.QueryRowContext(ctx, queueTableSQL, owner, name).Scan(&owner, &queueTable)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up queue %s: %w", queue, err)
+	}
+
+	// Limit the purge to the queue, including its messages in the exception queue
+	queueLiteral := quoteLiteral(strings.ToUpper(name))
+	purgeCondition := "(qtview.queue = " + queueLiteral + " OR qtview.original_queue_name = " + queueLiteral + ")"
+	if condition != "" {
+		purgeCondition += " AND (" + condition + ")"
+	}
+
+	var count int64
+	query := fmt.Sprintf(purgeCountSQL, quoteIdentifier(owner)+"."+quoteIdentifier("AQ$"+queueTable), purgeCondition)
+	err = a.db.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count messages to purge: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, purgeQueueTableSQL, owner+"."+queueTable, purgeCondition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge queue %s: %w", queue, err)
+	}
+
+	return count, nil
+}
+
+// quoteLiteral quotes a string for use as a SQL string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,68 @@
+package oraaq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectPurge sets up the queue table lookup and count for a purge.
+func expectPurge(mock sqlmock.Sqlmock, purgeCondition string, count int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(queueTableSQL)).
+		WithArgs("", "text_msg_queue").
+		WillReturnRows(sqlmock.NewRows([]string{"owner", "queue_table"}).AddRow("AQ", "TEXT_MSG_QUEUE_TABLE"))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(purgeCountSQL, `"AQ"."AQ$TEXT_MSG_QUEUE_TABLE"`, purgeCondition))).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestPurge_All(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	const purgeCondition = "(qtview.queue = 'TEXT_MSG_QUEUE' OR qtview.original_queue_name = 'TEXT_MSG_QUEUE')"
+	expectPurge(mock, purgeCondition, 3)
+	mock.ExpectExec(regexp.QuoteMeta(purgeQueueTableSQL)).
+		WithArgs("AQ.TEXT_MSG_QUEUE_TABLE", purgeCondition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	purged, err := admin.Purge(context.Background(), "text_msg_queue", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), purged)
+}
+
+func TestPurge_Expired(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	const purgeCondition = "(qtview.queue = 'TEXT_MSG_QUEUE' OR qtview.original_queue_name = 'TEXT_MSG_QUEUE')" +
+		" AND (qtview.msg_state = 'EXPIRED')"
+	expectPurge(mock, purgeCondition, 1)
+	mock.ExpectExec(regexp.QuoteMeta(purgeQueueTableSQL)).
+		WithArgs("AQ.TEXT_MSG_QUEUE_TABLE", purgeCondition).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	purged, err := admin.Purge(context.Background(), "text_msg_queue", PurgeExpired)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+}
+
+func TestPurge_Error(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	const purgeCondition = "(qtview.queue = 'TEXT_MSG_QUEUE' OR qtview.original_queue_name = 'TEXT_MSG_QUEUE')"
+	expectPurge(mock, purgeCondition, 3)
+	mock.ExpectExec(regexp.QuoteMeta(purgeQueueTableSQL)).
+		WithArgs("AQ.TEXT_MSG_QUEUE_TABLE", purgeCondition).
+		WillReturnError(errors.New("ORA-01031: insufficient privileges"))
+
+	purged, err := admin.Purge(context.Background(), "text_msg_queue", "")
+	require.ErrorContains(t, err, "ORA-01031")
+	require.Zero(t, purged)
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	require.Equal(t, "'O''BRIEN'", quoteLiteral("O'BRIEN"))
+}
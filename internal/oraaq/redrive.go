@@ -0,0 +1,151 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RedriveReport lists the messages Redrive moved, or would have moved on a dry run.
+type RedriveReport struct {
+	DryRun   bool
+	Messages []RedrivenMessage
+}
+
+// RedrivenMessage identifies a message moved from the exception queue.
+type RedrivenMessage struct {
+
+	// ID is the message ID the message had in the exception queue. It is given
+	// a new ID when it is enqueued to the original queue.
+	ID [16]byte
+
+	// ExceptionQueue is the exception queue the message was moved from.
+	ExceptionQueue string
+
+	// Correlation is the correlation identifier of the message.
+	Correlation string
+}
+
+// Redrive moves messages that expired or ran out of retries in queue back from
+// the exception queue to queue, oldest first, keeping their payload, priority,
+// correlation and expiration. Each message is moved in its own transaction.
+//
+// filter is an optional SQL predicate on the columns of the AQ$ view of the
+// queue table, such as corr_id or enq_timestamp, that selects the messages to
+// move. It is added to the query as is, so it must not come from untrusted input.
+// A limit of zero or less moves all the selected messages. On a dry run the
+// messages are only reported.
+//
+// Exception queues are not enabled for dequeueing by default, so Redrive
+// enables dequeueing on the exception queues it moves messages from.
+func (a *Admin) Redrive(ctx context.Context, queue string, filter string, limit int, dryRun bool) (RedriveReport, error) {
+
+	report := RedriveReport{DryRun: dryRun}
+
+	owner, name := splitQueueName(queue)
+	var queueTable string
+	err := a.db.QueryRowContext(ctx, queueTableSQL, owner, name).Scan(&owner, &queueTable)
+	if err != nil {
+		return report, fmt.Errorf("failed to look up queue %s: %w", queue, err)
+	}
+
+	candidates, err := a.redriveCandidates(ctx, owner, queueTable, strings.ToUpper(name), filter, limit)
+	if err != nil {
+		return report, err
+	}
+	if dryRun {
+		report.Messages = candidates
+		return report, nil
+	}
+
+	started := make(map[string]bool)
+	for _, msg := range candidates {
+
+		exceptionQueue := owner + "." + msg.ExceptionQueue
+		if !started[exceptionQueue] {
+			_, err = a.db.ExecContext(ctx, startDequeueSQL, exceptionQueue)
+			if err != nil {
+				return report, fmt.Errorf("failed to enable dequeue on %s: %w", exceptionQueue, err)
+			}
+			started[exceptionQueue] = true
+		}
+
+		err = a.redrive(ctx, exceptionQueue, owner+"."+name, msg.ID)
+		if err != nil {
+			return report, err
+		}
+		report.Messages = append(report.Messages, msg)
+	}
+
+	return report, nil
+}
+
+// redriveCandidates selects up to limit expired messages of the queue from the
+// AQ$ view of its queue table.
+func (a *Admin) redriveCandidates(ctx context.Context, owner string, queueTable string, queue string, filter string, limit int) ([]RedrivenMessage, error) {
+
+	filterClause := ""
+	if filter != "" {
+		filterClause = "\n\tAND    (" + filter + ")"
+	}
+	query := fmt.Sprintf(redriveCandidatesSQL, quoteIdentifier(owner)+"."+quoteIdentifier("AQ$"+queueTable), filterClause)
+
+	rows, err := a.db.QueryContext(ctx, query, queue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select messages to redrive: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var candidates []RedrivenMessage
+	for (limit <= 0 || len(candidates) < limit) && rows.Next() {
+		var msgID string
+		var exceptionQueue string
+		var correlation sql.NullString
+		err = rows.Scan(&msgID, &exceptionQueue, &correlation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message to redrive: %w", err)
+		}
+
+		decoded, err := hex.DecodeString(msgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode msgID: %w", err)
+		}
+		msg := RedrivenMessage{
+			ExceptionQueue: exceptionQueue,
+			Correlation:    correlation.String,
+		}
+		copy(msg.ID[:], decoded)
+		candidates = append(candidates, msg)
+	}
+
+	return candidates, rows.Err()
+}
+
+// redrive moves a single message from the exception queue to the original queue.
+func (a *Admin) redrive(ctx context.Context, exceptionQueue string, originalQueue string, msgID [16]byte) error {
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, redriveSQL, exceptionQueue, originalQueue, hex.EncodeToString(msgID[:]))
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to redrive message %X: %w", msgID, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// quoteIdentifier quotes an identifier read from the data dictionary for use in SQL.
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
@@ -0,0 +1,92 @@
+package oraaq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	redriveMsgID1 = "0102030405060708090A0B0C0D0E0F10"
+	redriveMsgID2 = "1112131415161718191A1B1C1D1E1F20"
+)
+
+// expectRedriveCandidates sets up the queue table lookup and candidate query.
+func expectRedriveCandidates(mock sqlmock.Sqlmock, filterClause string) {
+	mock.ExpectQuery(regexp.QuoteMeta(queueTableSQL)).
+		WithArgs("", "text_msg_queue").
+		WillReturnRows(sqlmock.NewRows([]string{"owner", "queue_table"}).AddRow("AQ", "TEXT_MSG_QUEUE_TABLE"))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(redriveCandidatesSQL, `"AQ"."AQ$TEXT_MSG_QUEUE_TABLE"`, filterClause))).
+		WithArgs("TEXT_MSG_QUEUE").
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id", "queue", "corr_id"}).
+			AddRow(redriveMsgID1, "AQ$_TEXT_MSG_QUEUE_TABLE_E", "first").
+			AddRow(redriveMsgID2, "AQ$_TEXT_MSG_QUEUE_TABLE_E", nil))
+}
+
+func TestRedrive_DryRun(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+	expectRedriveCandidates(mock, "\n\tAND    (corr_id = 'first')")
+
+	report, err := admin.Redrive(context.Background(), "text_msg_queue", "corr_id = 'first'", 0, true)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Len(t, report.Messages, 2, "A dry run should report every selected message")
+	require.Equal(t, "first", report.Messages[0].Correlation)
+	require.Equal(t, byte(0x01), report.Messages[0].ID[0])
+	require.Equal(t, "AQ$_TEXT_MSG_QUEUE_TABLE_E", report.Messages[1].ExceptionQueue)
+}
+
+func TestRedrive(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+	expectRedriveCandidates(mock, "")
+
+	// Only the first message is moved because of the limit
+	mock.ExpectExec(regexp.QuoteMeta(startDequeueSQL)).
+		WithArgs("AQ.AQ$_TEXT_MSG_QUEUE_TABLE_E").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(redriveSQL)).
+		WithArgs("AQ.AQ$_TEXT_MSG_QUEUE_TABLE_E", "AQ.text_msg_queue", "0102030405060708090a0b0c0d0e0f10").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	report, err := admin.Redrive(context.Background(), "text_msg_queue", "", 1, false)
+	require.NoError(t, err)
+	require.False(t, report.DryRun)
+	require.Len(t, report.Messages, 1)
+	require.Equal(t, "first", report.Messages[0].Correlation)
+}
+
+func TestRedrive_Error(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+	expectRedriveCandidates(mock, "")
+
+	mock.ExpectExec(regexp.QuoteMeta(startDequeueSQL)).
+		WithArgs("AQ.AQ$_TEXT_MSG_QUEUE_TABLE_E").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(redriveSQL)).
+		WithArgs("AQ.AQ$_TEXT_MSG_QUEUE_TABLE_E", "AQ.text_msg_queue", "0102030405060708090a0b0c0d0e0f10").
+		WillReturnError(errors.New("ORA-25228: timeout or end-of-fetch during message dequeue"))
+	mock.ExpectRollback()
+
+	report, err := admin.Redrive(context.Background(), "text_msg_queue", "", 0, false)
+	require.ErrorContains(t, err, "ORA-25228")
+	require.Empty(t, report.Messages, "No message should be reported as moved")
+}
+
+func TestRedrive_QueueNotFound(t *testing.T) {
+	admin, mock := newMockAdmin(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(queueTableSQL)).
+		WithArgs("aq", "pfft").
+		WillReturnRows(sqlmock.NewRows([]string{"owner", "queue_table"}))
+
+	_, err := admin.Redrive(context.Background(), "aq.pfft", "", 0, true)
+	require.Error(t, err)
+}
@@ -2,18 +2,36 @@ package oraaq
 
 const dequeueSQL = `Declare
     queue_name          Varchar2(255) := :1;
+    selected_msgid      Varchar2(32) := :2;
+    selected_corr       Varchar2(128) := :3;
+    selected_condition  Varchar2(4000) := :4;
+    consumer            Varchar2(128) := :5;
+    wait_seconds        Pls_Integer := :6;
     msgid               Raw(16);
     dequeue_options     DBMS_AQ.dequeue_options_t;
     message_properties  DBMS_AQ.message_properties_t;
     message             SYS.AQ$_JMS_TEXT_MESSAGE;
     extractedMessage    Clob;
+    replyAgent          SYS.AQ$_AGENT;
+    replyTo             Varchar2(128);
+    properties          Varchar2(32767);
                       
     errm                Varchar2(4000) := '';
             
 Begin
     dequeue_options.dequeue_mode   := sys.DBMS_AQ.REMOVE;
-    dequeue_options.wait           := DBMS_AQ.FOREVER;
+    dequeue_options.wait           := wait_seconds;
     dequeue_options.visibility     := DBMS_AQ.ON_COMMIT;
+
+    -- Select a specific message rather than the next one
+    If selected_msgid Is Not Null Then
+        dequeue_options.msgid := HEXTORAW(selected_msgid);
+    End If;
+    dequeue_options.correlation    := selected_corr;
+    dequeue_options.deq_condition  := selected_condition;
+
+    -- Queues with multiple consumers are dequeued as a subscriber
+    dequeue_options.consumer_name  := consumer;
     
     Begin
         DBMS_AQ.Dequeue(
@@ -24,6 +42,21 @@ Begin
             msgid               => msgid
         );
         extractedMessage := message.text_vc;
+        replyAgent := message.get_replyto;
+        replyTo := replyAgent.name;
+        -- User properties are returned URL encoded, as name=value pairs separated by &
+        If message.header.properties Is Not Null Then
+            For i In 1 .. message.header.properties.Count Loop
+                If message.header.properties(i).str_value Is Not Null Then
+                    If properties Is Not Null Then
+                        properties := properties || '&';
+                    End If;
+                    properties := properties ||
+                        UTL_URL.ESCAPE(message.header.properties(i).name, TRUE) || '=' ||
+                        UTL_URL.ESCAPE(message.header.properties(i).str_value, TRUE);
+                End If;
+            End Loop;
+        End If;
     Exception
         When Others Then
             msgid := UTL_RAW.CAST_TO_RAW('');  -- Set to NULL explicitly
@@ -31,9 +64,12 @@ Begin
             errm := SQLErrm;
     End;
 
-    :2 := extractedMessage;
-    :3 := RAWTOHEX(msgid);
-    :4 := errm; -- no error
+    :7 := extractedMessage;
+    :8 := RAWTOHEX(msgid);
+    :9 := message_properties.correlation;
+    :10 := replyTo;
+    :11 := errm; -- no error
+    :12 := properties;
 
 End;
 `
@@ -47,9 +83,32 @@ const enqueueSql = `
 
 	    queue_name          Varchar2(255) := :1;
 		msgContent 			Clob := :2;
+		msgCorrelation		Varchar2(128) := :3;
+		msgReplyTo			Varchar2(128) := :4;
+		msgProperties		Varchar2(32767) := :5;
+		position			Pls_Integer := 1;
+		separator			Pls_Integer;
+		property			Varchar2(32767);
+		equals				Pls_Integer;
 	BEGIN
 		message := SYS.AQ$_JMS_TEXT_MESSAGE.construct;
 		message.set_text(msgContent);
+		message_properties.correlation := msgCorrelation;
+		If msgReplyTo Is Not Null Then
+			message.set_replyto(SYS.AQ$_AGENT(msgReplyTo, NULL, NULL));
+		End If;
+
+		-- msgProperties is URL encoded, as name=value pairs separated by &
+		While position <= Length(msgProperties) Loop
+			separator := Instr(msgProperties || '&', '&', position);
+			property := Substr(msgProperties, position, separator - position);
+			equals := Instr(property, '=');
+			message.set_string_property(
+				UTL_URL.UNESCAPE(Substr(property, 1, equals - 1)),
+				UTL_URL.UNESCAPE(Substr(property, equals + 1))
+			);
+			position := separator + 1;
+		End Loop;
 		
 		DBMS_AQ.ENQUEUE(
 		  queue_name         => queue_name,
@@ -63,3 +122,361 @@ const enqueueSql = `
 	   
 	END;
 `
+
+const browseSQL = `Declare
+    queue_name          Varchar2(255) := :1;
+    first_message       Pls_Integer := :2;
+    consumer            Varchar2(128) := :3;
+    msgid               Raw(16);
+    dequeue_options     DBMS_AQ.dequeue_options_t;
+    message_properties  DBMS_AQ.message_properties_t;
+    message             SYS.AQ$_JMS_TEXT_MESSAGE;
+    extractedMessage    Clob;
+    replyAgent          SYS.AQ$_AGENT;
+    replyTo             Varchar2(128);
+    properties          Varchar2(32767);
+
+    errm                Varchar2(4000) := '';
+
+Begin
+    dequeue_options.dequeue_mode   := DBMS_AQ.BROWSE;
+    dequeue_options.wait           := DBMS_AQ.NO_WAIT;
+    If first_message = 1 Then
+        dequeue_options.navigation := DBMS_AQ.FIRST_MESSAGE;
+    Else
+        dequeue_options.navigation := DBMS_AQ.NEXT_MESSAGE;
+    End If;
+    dequeue_options.consumer_name  := consumer;
+
+    Begin
+        DBMS_AQ.Dequeue(
+            queue_name          => queue_name,
+            dequeue_options     => dequeue_options,
+            message_properties  => message_properties,
+            payload             => message,
+            msgid               => msgid
+        );
+        extractedMessage := message.text_vc;
+        replyAgent := message.get_replyto;
+        replyTo := replyAgent.name;
+        -- User properties are returned URL encoded, as name=value pairs separated by &
+        If message.header.properties Is Not Null Then
+            For i In 1 .. message.header.properties.Count Loop
+                If message.header.properties(i).str_value Is Not Null Then
+                    If properties Is Not Null Then
+                        properties := properties || '&';
+                    End If;
+                    properties := properties ||
+                        UTL_URL.ESCAPE(message.header.properties(i).name, TRUE) || '=' ||
+                        UTL_URL.ESCAPE(message.header.properties(i).str_value, TRUE);
+                End If;
+            End Loop;
+        End If;
+    Exception
+        When Others Then
+            msgid := UTL_RAW.CAST_TO_RAW('');  -- Set to NULL explicitly
+            extractedMessage := '';
+            errm := SQLErrm;
+    End;
+
+    :4 := extractedMessage;
+    :5 := RAWTOHEX(msgid);
+    :6 := message_properties.correlation;
+    :7 := replyTo;
+    :8 := errm; -- no error
+    :9 := properties;
+
+End;
+`
+
+const createQueueTableSQL = `
+	BEGIN
+		DBMS_AQADM.CREATE_QUEUE_TABLE(
+			queue_table        => :1,
+			queue_payload_type => :2,
+			multiple_consumers => :3 = 1,
+			storage_clause     => :4
+		);
+	END;
+`
+
+const dropQueueTableSQL = `
+	BEGIN
+		DBMS_AQADM.DROP_QUEUE_TABLE(
+			queue_table => :1,
+			force       => :2 = 1
+		);
+	END;
+`
+
+const createQueueSQL = `
+	BEGIN
+		DBMS_AQADM.CREATE_QUEUE(
+			queue_name     => :1,
+			queue_table    => :2,
+			max_retries    => :3,
+			retry_delay    => :4,
+			retention_time => :5
+		);
+	END;
+`
+
+const alterQueueSQL = `
+	BEGIN
+		DBMS_AQADM.ALTER_QUEUE(
+			queue_name     => :1,
+			max_retries    => :2,
+			retry_delay    => :3,
+			retention_time => :4
+		);
+	END;
+`
+
+const startQueueSQL = `
+	BEGIN
+		DBMS_AQADM.START_QUEUE(queue_name => :1);
+	END;
+`
+
+const stopQueueSQL = `
+	BEGIN
+		DBMS_AQADM.STOP_QUEUE(queue_name => :1);
+	END;
+`
+
+const dropQueueSQL = `
+	BEGIN
+		DBMS_AQADM.DROP_QUEUE(queue_name => :1);
+	END;
+`
+
+const grantQueuePrivilegeSQL = `
+	BEGIN
+		DBMS_AQADM.GRANT_QUEUE_PRIVILEGE(
+			privilege  => :1,
+			queue_name => :2,
+			grantee    => :3
+		);
+	END;
+`
+
+const statsSQL = `Declare
+    queue_owner         Varchar2(128) := Upper(Nvl(:1, User));
+    queue_name          Varchar2(128) := Upper(:2);
+    queue_table         Varchar2(128);
+    ready_count         Number;
+    waiting_count       Number;
+    expired_count       Number;
+    oldest_enqueued     Timestamp;
+
+Begin
+    Select q.queue_table
+    Into   queue_table
+    From   all_queues q
+    Where  q.owner = queue_owner
+    And    q.name = queue_name;
+
+    -- Read the counts from the AQ$<queue table> view. Expired messages have
+    -- been moved to the exception queue, and keep their original queue name.
+    Execute Immediate
+        'Select Count(Case When msg_state = ''READY'' And queue = :1 Then 1 End), ' ||
+        '       Count(Case When msg_state = ''WAITING'' And queue = :2 Then 1 End), ' ||
+        '       Count(Case When msg_state = ''EXPIRED'' And original_queue_name = :3 Then 1 End), ' ||
+        '       Min(Case When msg_state = ''READY'' And queue = :4 Then enq_timestamp End) ' ||
+        'From ' || DBMS_ASSERT.ENQUOTE_NAME(queue_owner, FALSE) || '.' ||
+                   DBMS_ASSERT.ENQUOTE_NAME('AQ$' || queue_table, FALSE)
+    Into  ready_count, waiting_count, expired_count, oldest_enqueued
+    Using queue_name, queue_name, queue_name, queue_name;
+
+    :3 := ready_count;
+    :4 := waiting_count;
+    :5 := expired_count;
+    :6 := To_Char(oldest_enqueued, 'YYYY-MM-DD"T"HH24:MI:SS.FF6');
+
+End;
+`
+
+const queueTableSQL = `
+	SELECT q.owner, q.queue_table
+	FROM   all_queues q
+	WHERE  q.owner = UPPER(NVL(:1, USER))
+	AND    q.name = UPPER(:2)
+`
+
+// redriveCandidatesSQL is completed with the quoted AQ$ view of the queue
+// table and the caller's filter.
+const redriveCandidatesSQL = `
+	SELECT RAWTOHEX(msg_id), queue, corr_id
+	FROM   %s
+	WHERE  msg_state = 'EXPIRED'
+	AND    original_queue_name = :1%s
+	ORDER  BY enq_timestamp
+`
+
+const startDequeueSQL = `
+	BEGIN
+		DBMS_AQADM.START_QUEUE(queue_name => :1, enqueue => FALSE, dequeue => TRUE);
+	END;
+`
+
+const redriveSQL = `Declare
+    exception_queue     Varchar2(261) := :1;
+    original_queue      Varchar2(261) := :2;
+    selected_msgid      Raw(16) := HEXTORAW(:3);
+    dequeue_options     DBMS_AQ.dequeue_options_t;
+    enqueue_options     DBMS_AQ.enqueue_options_t;
+    dequeued_properties DBMS_AQ.message_properties_t;
+    enqueue_properties  DBMS_AQ.message_properties_t;
+    message             SYS.AQ$_JMS_TEXT_MESSAGE;
+    msgid               Raw(16);
+
+Begin
+    dequeue_options.dequeue_mode   := DBMS_AQ.REMOVE;
+    dequeue_options.wait           := DBMS_AQ.NO_WAIT;
+    dequeue_options.visibility     := DBMS_AQ.ON_COMMIT;
+    dequeue_options.msgid          := selected_msgid;
+
+    DBMS_AQ.Dequeue(
+        queue_name          => exception_queue,
+        dequeue_options     => dequeue_options,
+        message_properties  => dequeued_properties,
+        payload             => message,
+        msgid               => msgid
+    );
+
+    -- Keep the properties that describe the message, but not its delivery history
+    enqueue_properties.priority        := dequeued_properties.priority;
+    enqueue_properties.correlation     := dequeued_properties.correlation;
+    enqueue_properties.expiration      := dequeued_properties.expiration;
+    enqueue_properties.exception_queue := dequeued_properties.exception_queue;
+    enqueue_properties.sender_id       := dequeued_properties.sender_id;
+
+    DBMS_AQ.Enqueue(
+        queue_name          => original_queue,
+        enqueue_options     => enqueue_options,
+        message_properties  => enqueue_properties,
+        payload             => message,
+        msgid               => msgid
+    );
+
+End;
+`
+
+// purgeCountSQL is completed with the quoted AQ$ view of the queue table and
+// the purge condition.
+const purgeCountSQL = `
+	SELECT COUNT(*)
+	FROM   %s qtview
+	WHERE  %s
+`
+
+const purgeQueueTableSQL = `
+	DECLARE
+		purge_options	DBMS_AQADM.AQ$_PURGE_OPTIONS_T;
+	BEGIN
+		DBMS_AQADM.PURGE_QUEUE_TABLE(
+			queue_table     => :1,
+			purge_condition => :2,
+			purge_options   => purge_options
+		);
+	END;
+`
+
+const createEventQueueSQL = `
+	BEGIN
+		DBMS_AQADM.CREATE_TRANSACTIONAL_EVENT_QUEUE(
+			queue_name         => :1,
+			multiple_consumers => :2 = 1,
+			max_retries        => :3,
+			queue_payload_type => :4,
+			storage_clause     => :5
+		);
+	END;
+`
+
+const dropEventQueueSQL = `
+	BEGIN
+		DBMS_AQADM.DROP_TRANSACTIONAL_EVENT_QUEUE(queue_name => :1);
+	END;
+`
+
+const setQueueParameterSQL = `
+	BEGIN
+		DBMS_AQADM.SET_QUEUE_PARAMETER(
+			queue_name => :1,
+			param_name => :2,
+			param_value => :3
+		);
+	END;
+`
+
+const addSubscriberSQL = `
+	BEGIN
+		DBMS_AQADM.ADD_SUBSCRIBER(
+			queue_name => :1,
+			subscriber => SYS.AQ$_AGENT(:2, NULL, NULL)
+		);
+	END;
+`
+
+const removeSubscriberSQL = `
+	BEGIN
+		DBMS_AQADM.REMOVE_SUBSCRIBER(
+			queue_name => :1,
+			subscriber => SYS.AQ$_AGENT(:2, NULL, NULL)
+		);
+	END;
+`
+
+const listenSQL = `Declare
+    queue_names         Varchar2(32767) := :1;
+    consumer            Varchar2(128) := :2;
+    agent_list          DBMS_AQ.aq$_agent_list_t;
+    agent               SYS.AQ$_AGENT;
+    position            Pls_Integer := 1;
+    separator           Pls_Integer;
+
+    errm                Varchar2(4000) := '';
+
+Begin
+    -- queue_names is a comma separated list, with an agent listening on each queue
+    Loop
+        separator := Instr(queue_names || ',', ',', position);
+        agent_list(agent_list.Count + 1) := SYS.AQ$_AGENT(consumer, Substr(queue_names, position, separator - position), NULL);
+        position := separator + 1;
+        Exit When position > Length(queue_names);
+    End Loop;
+
+    Begin
+        DBMS_AQ.LISTEN(
+            agent_list          => agent_list,
+            wait                => DBMS_AQ.FOREVER,
+            agent               => agent
+        );
+    Exception
+        When Others Then
+            errm := SQLErrm;
+    End;
+
+    :3 := agent.address;
+    :4 := errm; -- no error
+
+End;
+`
+
+// InsertIdempotencyKeySQL inserts an idempotency key into an idempotency table, whose
+// name is formatted into the statement.
+const InsertIdempotencyKeySQL = `INSERT INTO %s (idempotency_key) VALUES (:1)`
+
+// IdempotencyTableSQL creates an idempotency table, whose name is formatted into the
+// statement. The tables of dedup.SQLStore are created with it too.
+const IdempotencyTableSQL = `
+	CREATE TABLE %s (
+		idempotency_key VARCHAR2(255) PRIMARY KEY,
+		processed_at    TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+	)
+`
+
+// DeleteIdempotencyKeysBeforeSQL deletes the keys inserted into an idempotency table,
+// whose name is formatted into the statement, before a time.
+const DeleteIdempotencyKeysBeforeSQL = `DELETE FROM %s WHERE processed_at < :1`
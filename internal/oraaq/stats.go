@@ -0,0 +1,74 @@
+package oraaq
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	go_ora "github.com/sijms/go-ora/v2"
+	"strings"
+	"time"
+)
+
+// timestampLayout matches the format statsSQL returns the oldest enqueue time in.
+const timestampLayout = "2006-01-02T15:04:05.999999"
+
+// Stats reads the number of ready, waiting and expired messages in the queue,
+// and the enqueue time of the oldest ready message, from the AQ$ view of the
+// queue's queue table. Expired messages are those moved to the exception queue.
+func (d *Dequeuer) Stats(ctx context.Context) (api.Stats, error) {
+
+	owner, name := splitQueueName(d.queueName)
+
+	var ready, waiting, expired int64
+	var oldest sql.NullString
+
+	_, err := d.db.ExecContext(ctx, statsSQL,
+		owner,
+		name,
+		go_ora.Out{Dest: &ready},
+		go_ora.Out{Dest: &waiting},
+		go_ora.Out{Dest: &expired},
+		go_ora.Out{Dest: &oldest, Size: 32},
+	)
+	if err != nil {
+		return api.Stats{}, fmt.Errorf("failed to read queue stats: %w", oracleError(err))
+	}
+
+	oldestEnqueued, err := parseTimestamp(oldest.String)
+	if err != nil {
+		return api.Stats{}, err
+	}
+
+	return api.Stats{
+		Ready:          ready,
+		Waiting:        waiting,
+		Expired:        expired,
+		OldestEnqueued: oldestEnqueued,
+	}, nil
+}
+
+// splitQueueName splits a queue name into its schema, which is empty for the
+// current user's schema, and its name.
+func splitQueueName(queueName string) (string, string) {
+	owner, name, found := strings.Cut(queueName, ".")
+	if !found {
+		return "", queueName
+	}
+	return owner, name
+}
+
+// parseTimestamp parses a timestamp formatted by statsSQL, returning the zero
+// time for an empty string. The timestamp is interpreted as UTC, the time
+// zone AQ records enqueue times in.
+func parseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.ParseInLocation(timestampLayout, s, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse enqueue time: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,57 @@
+package oraaq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitQueueName(t *testing.T) {
+	owner, name := splitQueueName("text_msg_queue")
+	require.Equal(t, "", owner, "An unqualified queue should be in the current schema")
+	require.Equal(t, "text_msg_queue", name)
+
+	owner, name = splitQueueName("aq_owner.text_msg_queue")
+	require.Equal(t, "aq_owner", owner)
+	require.Equal(t, "text_msg_queue", name)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	ts, err := parseTimestamp("2024-05-01T13:14:15.123456")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 5, 1, 13, 14, 15, 123456000, time.UTC), ts)
+
+	ts, err = parseTimestamp("")
+	require.NoError(t, err)
+	require.True(t, ts.IsZero(), "An empty timestamp should be the zero time")
+
+	_, err = parseTimestamp("pfft")
+	require.Error(t, err)
+}
+
+func (suite *DequeuerTestSuite) TestStats() {
+	dequeuer := NewDequeuer(suite.db, "text_msg_queue")
+	enqueuer := NewEnqueuer(suite.db, "text_msg_queue")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := dequeuer.Stats(ctx)
+	suite.Require().NoError(err, "Failed to read stats")
+	suite.Zero(stats.Ready)
+	suite.True(stats.OldestEnqueued.IsZero())
+
+	before := time.Now().Add(-time.Minute)
+	for i := 0; i < 2; i++ {
+		suite.Require().NoError(enqueuer.Enqueue(ctx, &Message{Content: "test message"}))
+	}
+
+	stats, err = dequeuer.Stats(ctx)
+	suite.Require().NoError(err, "Failed to read stats")
+	suite.Equal(int64(2), stats.Ready)
+	suite.Zero(stats.Waiting)
+	suite.Zero(stats.Expired)
+	suite.True(stats.OldestEnqueued.After(before), "The oldest message should have been enqueued during the test")
+}
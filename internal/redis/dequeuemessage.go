@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	goredis "github.com/redis/go-redis/v9"
+	"time"
+)
+
+type DequeueMessage struct {
+	message      Message
+	client       goredis.UniversalClient
+	stream       string
+	group        string
+	consumer     string
+	reclaimAfter time.Duration
+}
+
+func (d *DequeueMessage) Message() api.Message[Message] {
+	return &d.message
+}
+
+// Ack removes the entry from the consumer group's pending entries list with XACK.
+func (d *DequeueMessage) Ack(ctx context.Context) error {
+	return d.client.XAck(ctx, d.stream, d.group, d.message.ID).Err()
+}
+
+// NAck leaves the entry pending, but re-claims it with its idle time set to the
+// Dequeuer's reclaim threshold, so that the next Dequeue of any consumer in the
+// group reclaims it straight away. The delivery count is left unchanged; it is
+// incremented when the entry is reclaimed.
+func (d *DequeueMessage) NAck(ctx context.Context) error {
+	return d.client.Do(ctx, "XCLAIM",
+		d.stream, d.group, d.consumer, 0, d.message.ID,
+		"IDLE", d.reclaimAfter.Milliseconds(),
+		"RETRYCOUNT", d.message.DeliveryCount,
+		"JUSTID",
+	).Err()
+}
@@ -0,0 +1,196 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	goredis "github.com/redis/go-redis/v9"
+	"time"
+)
+
+// reclaimBatch is the number of pending entries inspected per reclaim attempt.
+const reclaimBatch = 10
+
+func NewDequeuer(client goredis.UniversalClient, stream, group, consumer string, block, reclaimAfter time.Duration) *Dequeuer {
+	return &Dequeuer{
+		client:       client,
+		stream:       stream,
+		group:        group,
+		consumer:     consumer,
+		block:        block,
+		reclaimAfter: reclaimAfter,
+	}
+}
+
+// Dequeuer reads entries from a Redis stream as a named consumer in a consumer group.
+type Dequeuer struct {
+
+	// client is the Redis client. It maintains a connection
+	// pool and is safe for concurrent use.
+	client goredis.UniversalClient
+
+	// The Redis stream, consumer group and consumer name
+	// that the Dequeuer is bound to.
+	stream   string
+	group    string
+	consumer string
+
+	// block is how long a single XREADGROUP waits for new entries.
+	block time.Duration
+
+	// reclaimAfter is how long an entry must have been pending without
+	// being acknowledged before it is reclaimed from its consumer.
+	reclaimAfter time.Duration
+}
+
+// Dequeue returns the next entry for this consumer. Entries that have been
+// pending for longer than the reclaim threshold, because they were NAcked or
+// their consumer crashed, are reclaimed first; otherwise a new entry is read
+// with XREADGROUP. It blocks until an entry is available or until the context
+// is cancelled, in which case the context's error is returned.
+func (d *Dequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Reclaim stale pending entries
+		msg, err := d.reclaim(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if msg != nil {
+			return msg, nil
+		}
+
+		// Read a new entry, blocking no longer than the context allows, since
+		// a blocked XREADGROUP does not return when the context is done
+		block, ok := d.blockFor(ctx)
+		if !ok {
+			return nil, context.DeadlineExceeded
+		}
+		streams, err := d.client.XReadGroup(ctx, &goredis.XReadGroupArgs{
+			Group:    d.group,
+			Consumer: d.consumer,
+			Streams:  []string{d.stream, ">"},
+			Count:    1,
+			Block:    block,
+		}).Result()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		for _, stream := range streams {
+			for _, entry := range stream.Messages {
+				return d.newDequeueMessage(entry, 1), nil
+			}
+		}
+	}
+}
+
+// blockFor returns how long XREADGROUP may block: the Dequeuer's block, capped by
+// the context's deadline. It returns false if the deadline leaves less than the
+// millisecond XREADGROUP blocks for at least, as a BLOCK of 0 would wait forever.
+func (d *Dequeuer) blockFor(ctx context.Context) (time.Duration, bool) {
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return d.block, true
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < time.Millisecond {
+		return 0, false
+	}
+
+	return min(d.block, remaining), true
+}
+
+// reclaim claims the first entry of the group that has been pending for longer
+// than reclaimAfter. It returns nil if there is none. Only entries idle for that
+// long are listed, so that entries still being handled do not hide stale ones.
+func (d *Dequeuer) reclaim(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	pending, err := d.client.XPendingExt(ctx, &goredis.XPendingExtArgs{
+		Stream: d.stream,
+		Group:  d.group,
+		Idle:   d.reclaimAfter,
+		Start:  "-",
+		End:    "+",
+		Count:  reclaimBatch,
+	}).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pending {
+		if p.Idle < d.reclaimAfter {
+			continue
+		}
+
+		// XCLAIM re-checks the idle time, so only one consumer wins the entry
+		claimed, err := d.client.XClaim(ctx, &goredis.XClaimArgs{
+			Stream:   d.stream,
+			Group:    d.group,
+			Consumer: d.consumer,
+			MinIdle:  d.reclaimAfter,
+			Messages: []string{p.ID},
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range claimed {
+			return d.newDequeueMessage(entry, p.RetryCount+1), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (d *Dequeuer) newDequeueMessage(entry goredis.XMessage, deliveryCount int64) api.DequeueMessage[Message] {
+
+	message := Message{
+		ID:            entry.ID,
+		Fields:        make(map[string]string, len(entry.Values)),
+		DeliveryCount: deliveryCount,
+	}
+	for key, val := range entry.Values {
+		str, _ := val.(string)
+		if key == contentField {
+			message.Content = str
+			continue
+		}
+		message.Fields[key] = str
+	}
+
+	return &DequeueMessage{
+		message:      message,
+		client:       d.client,
+		stream:       d.stream,
+		group:        d.group,
+		consumer:     d.consumer,
+		reclaimAfter: d.reclaimAfter,
+	}
+}
+
+func (d *Dequeuer) Disconnect(_ context.Context) error {
+
+	err := d.client.Close()
+	if err != nil {
+		return err
+	}
+
+	d.client = nil
+	return nil
+}
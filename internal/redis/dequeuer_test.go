@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+func TestDequeuerTestSuite(t *testing.T) {
+	suite.Run(t, new(DequeuerTestSuite))
+}
+
+type DequeuerTestSuite struct {
+	suite.Suite
+	srv    *miniredis.Miniredis
+	client *goredis.Client
+}
+
+// SetupTest starts an in-memory Redis with a "text_msg_stream" stream and a
+// "test_group" consumer group on it.
+func (suite *DequeuerTestSuite) SetupTest() {
+	suite.srv = miniredis.RunT(suite.T())
+	suite.client = goredis.NewClient(&goredis.Options{Addr: suite.srv.Addr()})
+
+	err := suite.client.XGroupCreateMkStream(context.Background(), "text_msg_stream", "test_group", "0").Err()
+	suite.Require().NoError(err)
+}
+
+func (suite *DequeuerTestSuite) TearDownTest() {
+	_ = suite.client.Close()
+}
+
+func (suite *DequeuerTestSuite) newDequeuer(consumer string, reclaimAfter time.Duration) *Dequeuer {
+	client := goredis.NewClient(&goredis.Options{Addr: suite.srv.Addr()})
+	return NewDequeuer(client, "text_msg_stream", "test_group", consumer, 50*time.Millisecond, reclaimAfter)
+}
+
+func (suite *DequeuerTestSuite) add(content string) {
+	err := suite.client.XAdd(context.Background(), &goredis.XAddArgs{
+		Stream: "text_msg_stream",
+		Values: map[string]interface{}{contentField: content, "h": "v"},
+	}).Err()
+	suite.Require().NoError(err)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueEmptyStream() {
+	dequeuer := suite.newDequeuer("c1", time.Minute)
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	deqMsg, err := dequeuer.Dequeue(ctx)
+
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Nil(deqMsg, "DequeueMessage should be nil when there is an error")
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndAck() {
+	suite.add("test message")
+	dequeuer := suite.newDequeuer("c1", time.Minute)
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx := context.Background()
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.Equal(map[string]string{"h": "v"}, deqMsg.Message().Raw().Fields)
+	suite.Equal(int64(1), deqMsg.Message().Raw().DeliveryCount)
+
+	suite.Require().NoError(deqMsg.Ack(ctx))
+
+	pending, err := suite.client.XPending(ctx, "text_msg_stream", "test_group").Result()
+	suite.Require().NoError(err)
+	suite.Zero(pending.Count, "Acked entry should no longer be pending")
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndNAck() {
+	suite.add("test message")
+	dequeuer := suite.newDequeuer("c1", time.Minute)
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Require().NoError(deqMsg.NAck(ctx))
+
+	// The NAcked entry must be reclaimed straight away, despite the long threshold
+	deqMsg2, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to re-dequeue message")
+	suite.Equal(deqMsg.Message().Raw().ID, deqMsg2.Message().Raw().ID)
+	suite.Equal("test message", deqMsg2.Message().Text(), "Re-dequeued message should still be identical")
+	suite.Equal(int64(2), deqMsg2.Message().Raw().DeliveryCount)
+}
+
+func (suite *DequeuerTestSuite) TestReclaimFromCrashedConsumer() {
+	suite.add("test message")
+
+	// c1 reads the entry and never acknowledges it
+	crashed := suite.newDequeuer("c1", 100*time.Millisecond)
+	deqMsg, err := crashed.Dequeue(context.Background())
+	suite.Require().NoError(err)
+	_ = crashed.Disconnect(context.Background())
+
+	// c2 reclaims it once it has been pending for longer than the threshold
+	dequeuer := suite.newDequeuer("c2", 100*time.Millisecond)
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	reclaimed, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(deqMsg.Message().Raw().ID, reclaimed.Message().Raw().ID)
+	suite.Require().NoError(reclaimed.Ack(ctx))
+}
+
+func (suite *DequeuerTestSuite) TestReclaimBehindInFlightEntries() {
+	dequeuer := suite.newDequeuer("c1", time.Minute)
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// More entries than a reclaim inspects at once are still being handled
+	for i := 0; i <= reclaimBatch; i++ {
+		suite.add("in flight")
+		_, err := dequeuer.Dequeue(ctx)
+		suite.Require().NoError(err)
+	}
+
+	// The NAcked entry behind them must still be reclaimed
+	suite.add("nacked")
+	deqMsg, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Equal("nacked", deqMsg.Message().Text())
+	suite.Require().NoError(deqMsg.NAck(ctx))
+
+	reclaimed, err := dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(deqMsg.Message().Raw().ID, reclaimed.Message().Raw().ID)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueBlockCappedByDeadline() {
+	client := goredis.NewClient(&goredis.Options{Addr: suite.srv.Addr()})
+	dequeuer := NewDequeuer(client, "text_msg_stream", "test_group", "c1", time.Minute, time.Minute)
+	defer func() { _ = dequeuer.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := dequeuer.Dequeue(ctx)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Less(time.Since(start), 5*time.Second, "Dequeue should return at the context's deadline")
+}
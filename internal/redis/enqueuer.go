@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func NewEnqueuer(client goredis.UniversalClient, stream string) *Enqueuer {
+	return &Enqueuer{
+		client: client,
+		stream: stream,
+	}
+}
+
+// Enqueuer appends messages to a Redis stream.
+type Enqueuer struct {
+
+	// client is the Redis client. It maintains a connection
+	// pool and is safe for concurrent use.
+	client goredis.UniversalClient
+
+	// The Redis stream that the Enqueuer is bound to.
+	stream string
+}
+
+// NewMessage returns a new, empty instance of `Message` that implements the `api.Message` interface.
+func (e *Enqueuer) NewMessage() api.Message[Message] {
+	return &Message{}
+}
+
+// Enqueue appends a message to the bound stream with XADD.
+func (e *Enqueuer) Enqueue(ctx context.Context, msg api.Message[Message]) error {
+
+	raw := msg.Raw()
+	values := make(map[string]interface{}, len(raw.Fields)+1)
+	for key, val := range raw.Fields {
+		values[key] = val
+	}
+	values[contentField] = raw.Content
+
+	err := e.client.XAdd(ctx, &goredis.XAddArgs{
+		Stream: e.stream,
+		Values: values,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Enqueuer) Disconnect(_ context.Context) error {
+
+	err := e.client.Close()
+	if err != nil {
+		return err
+	}
+
+	e.client = nil
+	e.stream = ""
+	return nil
+}
@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type EnqueuerTestSuite struct {
+	suite.Suite
+	srv *miniredis.Miniredis
+}
+
+func TestEnqueuerTestSuite(t *testing.T) {
+	suite.Run(t, new(EnqueuerTestSuite))
+}
+
+func (suite *EnqueuerTestSuite) SetupTest() {
+	suite.srv = miniredis.RunT(suite.T())
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue() {
+	client := goredis.NewClient(&goredis.Options{Addr: suite.srv.Addr()})
+	enqueuer := NewEnqueuer(client, "text_msg_stream")
+	defer func() { _ = enqueuer.Disconnect(context.Background()) }()
+
+	message := &Message{
+		Content: "test message",
+		Fields:  map[string]string{"h": "v"},
+	}
+	err := enqueuer.Enqueue(context.Background(), message)
+	suite.NoError(err, "Failed to enqueue message")
+
+	entries, err := client.XRange(context.Background(), "text_msg_stream", "-", "+").Result()
+	suite.Require().NoError(err)
+	suite.Require().Len(entries, 1)
+	suite.Equal(map[string]interface{}{contentField: "test message", "h": "v"}, entries[0].Values)
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_Error() {
+	client := goredis.NewClient(&goredis.Options{Addr: suite.srv.Addr()})
+	enqueuer := NewEnqueuer(client, "text_msg_stream")
+	defer func() { _ = enqueuer.Disconnect(context.Background()) }()
+
+	// A plain key of the same name makes XADD fail with WRONGTYPE
+	suite.Require().NoError(suite.srv.Set("text_msg_stream", "not a stream"))
+
+	err := enqueuer.Enqueue(context.Background(), &Message{Content: "test message"})
+	suite.Error(err, "Expected error during enqueue because the key is not a stream.")
+}
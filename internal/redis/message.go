@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// contentField is the stream entry field that holds the message content.
+const contentField = "content"
+
+// Message is a Redis stream entry as seen by ezQue. Content is stored in the
+// entry's "content" field and Fields holds any further fields of the entry.
+// ID and DeliveryCount are populated on Dequeue and are ignored on Enqueue.
+type Message struct {
+	ID            string
+	Content       string
+	Fields        map[string]string
+	DeliveryCount int64
+}
+
+func (m *Message) Raw() Message {
+	return *m
+}
+
+func (m *Message) Text() string {
+	return m.Content
+}
+
+func (m *Message) SetRaw(raw Message) {
+	*m = raw
+}
+
+func (m *Message) SetText(msg string) {
+	m.Content = msg
+}
+
+// GetProperty returns the named entry field, implementing api.Properties.
+func (m *Message) GetProperty(key string) string {
+	return m.Fields[key]
+}
+
+// SetProperty sets the named entry field, implementing api.Properties. The
+// "content" field is reserved for the message content, so a property of that
+// name is dropped on Enqueue.
+func (m *Message) SetProperty(key string, value string) {
+	if m.Fields == nil {
+		m.Fields = make(map[string]string)
+	}
+	m.Fields[key] = value
+}
+
+// PropertyKeys returns the names of the entry fields, implementing
+// api.Properties.
+func (m *Message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.Fields))
+	for key := range m.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetEnqueuedAt returns the time in milliseconds that the entry ID starts with,
+// implementing api.Timestamped. Redis assigns it when the entry is added.
+func (m *Message) GetEnqueuedAt() time.Time {
+	millis, _, ok := strings.Cut(m.ID, "-")
+	if !ok {
+		return time.Time{}
+	}
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
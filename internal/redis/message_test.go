@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaw(t *testing.T) {
+	message := &Message{
+		ID:      "1-0",
+		Content: "testContent",
+		Fields:  map[string]string{"h": "v"},
+	}
+
+	raw := message.Raw()
+
+	// check if the properties of the raw message match those of the original message
+	require.Equal(t, message.ID, raw.ID, "Raw ID does not match the original message's ID")
+	require.Equal(t, message.Content, raw.Content, "Raw Content does not match the original message's Content")
+	require.Equal(t, message.Fields, raw.Fields, "Raw Fields do not match the original message's Fields")
+}
+
+func TestText(t *testing.T) {
+	const content = "testContent"
+
+	message := &Message{}
+	message.SetText(content)
+
+	// Text() should return the Content property of the message
+	require.Equal(t, content, message.Text(), "Text does not return the correct message Content")
+}
+
+func TestProperties(t *testing.T) {
+	var properties api.Properties = &Message{}
+	properties.SetProperty("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	properties.SetProperty("count", "3")
+
+	// the api.Properties methods should map onto the entry fields
+	message := properties.(*Message)
+	require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", message.Fields["traceparent"])
+	require.Equal(t, "3", properties.GetProperty("count"))
+	require.Equal(t, "", properties.GetProperty("missing"))
+	require.Equal(t, []string{"count", "traceparent"}, properties.PropertyKeys())
+}
+
+func TestEnqueuedAt(t *testing.T) {
+	var timestamped api.Timestamped = &Message{ID: "1700000000123-4"}
+	require.Equal(t, time.UnixMilli(1700000000123), timestamped.GetEnqueuedAt())
+
+	// messages that were not dequeued have no ID to take the time from
+	require.True(t, (&Message{}).GetEnqueuedAt().IsZero())
+}
@@ -0,0 +1,38 @@
+package sqs
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+type DequeueMessage struct {
+	message  Message
+	client   *sqs.Client
+	queueURL string
+}
+
+func (d *DequeueMessage) Message() api.Message[Message] {
+	return &d.message
+}
+
+// Ack deletes the message from the queue.
+func (d *DequeueMessage) Ack(ctx context.Context) error {
+	_, err := d.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(d.queueURL),
+		ReceiptHandle: aws.String(d.message.ReceiptHandle),
+	})
+	return err
+}
+
+// NAck sets the message's visibility timeout to zero, making it available
+// to be received again straight away.
+func (d *DequeueMessage) NAck(ctx context.Context) error {
+	_, err := d.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(d.queueURL),
+		ReceiptHandle:     aws.String(d.message.ReceiptHandle),
+		VisibilityTimeout: 0,
+	})
+	return err
+}
@@ -0,0 +1,120 @@
+package sqs
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/pgvanniekerk/ezQue/api"
+	"strconv"
+	"time"
+)
+
+func NewDequeuer(client *sqs.Client, queueURL string, waitTimeSeconds int32, visibilityTimeout int32) *Dequeuer {
+	return &Dequeuer{
+		client:            client,
+		queueURL:          queueURL,
+		waitTimeSeconds:   waitTimeSeconds,
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+// Dequeuer receives messages from a single SQS queue using long polling.
+type Dequeuer struct {
+
+	// client is the SQS client. It is safe for concurrent use.
+	client *sqs.Client
+
+	// The URL of the SQS queue that the Dequeuer is bound to.
+	queueURL string
+
+	// waitTimeSeconds is how long a single ReceiveMessage call waits
+	// for a message to arrive, up to SQS's maximum of 20 seconds.
+	waitTimeSeconds int32
+
+	// visibilityTimeout overrides the queue's visibility timeout for
+	// received messages when it is greater than zero.
+	visibilityTimeout int32
+}
+
+// Dequeue receives the next message from the queue. It blocks until a message
+// is available or until the context is cancelled, in which case the context's
+// error is returned.
+func (d *Dequeuer) Dequeue(ctx context.Context) (api.DequeueMessage[Message], error) {
+
+	msgs, err := d.DequeueBatch(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs[0], nil
+}
+
+// DequeueBatch receives up to limit messages, and at most ten, from the queue.
+// It blocks until at least one message is available or until the context is
+// cancelled.
+func (d *Dequeuer) DequeueBatch(ctx context.Context, limit int) ([]api.DequeueMessage[Message], error) {
+
+	limit = min(max(limit, 1), maxBatch)
+
+	for {
+		out, err := d.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:                    aws.String(d.queueURL),
+			MaxNumberOfMessages:         int32(limit),
+			WaitTimeSeconds:             d.waitTimeSeconds,
+			VisibilityTimeout:           d.visibilityTimeout,
+			MessageAttributeNames:       []string{"All"},
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		if len(out.Messages) == 0 {
+			continue
+		}
+
+		msgs := make([]api.DequeueMessage[Message], 0, len(out.Messages))
+		for _, m := range out.Messages {
+			msgs = append(msgs, d.newDequeueMessage(m))
+		}
+		return msgs, nil
+	}
+}
+
+func (d *Dequeuer) newDequeueMessage(m types.Message) api.DequeueMessage[Message] {
+
+	message := Message{
+		ID:              aws.ToString(m.MessageId),
+		Content:         aws.ToString(m.Body),
+		Attributes:      make(map[string]string, len(m.MessageAttributes)),
+		GroupID:         m.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)],
+		DeduplicationID: m.Attributes[string(types.MessageSystemAttributeNameMessageDeduplicationId)],
+		ReceiptHandle:   aws.ToString(m.ReceiptHandle),
+	}
+	for key, val := range m.MessageAttributes {
+		message.Attributes[key] = aws.ToString(val.StringValue)
+	}
+	if count, err := strconv.Atoi(m.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]); err == nil {
+		message.ReceiveCount = count
+	}
+	if sent, err := strconv.ParseInt(m.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+		message.SentAt = time.UnixMilli(sent)
+	}
+
+	return &DequeueMessage{
+		message:  message,
+		client:   d.client,
+		queueURL: d.queueURL,
+	}
+}
+
+func (d *Dequeuer) Disconnect(_ context.Context) error {
+
+	d.client = nil
+	d.queueURL = ""
+	return nil
+}
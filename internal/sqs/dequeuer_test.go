@@ -0,0 +1,106 @@
+package sqs
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/sqs/sqstest"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+// newClient returns an SQS client for the given stand-in server.
+func newClient(srv *sqstest.Server) *sqs.Client {
+	return sqs.New(sqs.Options{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(srv.URL),
+	})
+}
+
+func TestDequeuerTestSuite(t *testing.T) {
+	suite.Run(t, new(DequeuerTestSuite))
+}
+
+type DequeuerTestSuite struct {
+	suite.Suite
+	srv      *sqstest.Server
+	queueURL string
+	enqueuer *Enqueuer
+	dequeuer *Dequeuer
+}
+
+func (suite *DequeuerTestSuite) SetupTest() {
+	suite.srv = sqstest.NewServer()
+	suite.queueURL = suite.srv.CreateQueue("text_msg_queue")
+	suite.enqueuer = NewEnqueuer(newClient(suite.srv), suite.queueURL, "")
+	suite.dequeuer = NewDequeuer(newClient(suite.srv), suite.queueURL, 1, 0)
+}
+
+func (suite *DequeuerTestSuite) TearDownTest() {
+	suite.srv.Close()
+}
+
+func (suite *DequeuerTestSuite) TestDequeueEmptyQueue() {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Nil(deqMsg, "DequeueMessage should be nil when there is an error")
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndAck() {
+	ctx := context.Background()
+	suite.Require().NoError(suite.enqueuer.Enqueue(ctx, &Message{
+		Content:    "test message",
+		Attributes: map[string]string{"h": "v"},
+	}))
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.Equal(map[string]string{"h": "v"}, deqMsg.Message().Raw().Attributes)
+	suite.Equal(1, deqMsg.Message().Raw().ReceiveCount)
+	suite.False(deqMsg.Message().Raw().SentAt.IsZero())
+
+	suite.Require().NoError(deqMsg.Ack(ctx))
+	suite.Zero(suite.srv.Len("text_msg_queue"), "Acked message should have been deleted")
+}
+
+func (suite *DequeuerTestSuite) TestDequeueWithMessageAndNAck() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	suite.Require().NoError(suite.enqueuer.Enqueue(ctx, &Message{Content: "test message"}))
+
+	deqMsg, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Require().NoError(deqMsg.NAck(ctx))
+
+	// The message must be visible again straight away
+	deqMsg2, err := suite.dequeuer.Dequeue(ctx)
+	suite.Require().NoError(err, "Failed to re-dequeue message")
+	suite.Equal(deqMsg.Message().Raw().ID, deqMsg2.Message().Raw().ID)
+	suite.Equal(2, deqMsg2.Message().Raw().ReceiveCount)
+}
+
+func (suite *DequeuerTestSuite) TestDequeueBatch() {
+	ctx := context.Background()
+	msgs := make([]api.Message[Message], 0, 15)
+	for i := 0; i < 15; i++ {
+		msgs = append(msgs, &Message{Content: "test message"})
+	}
+	suite.Require().NoError(suite.enqueuer.EnqueueBatch(ctx, msgs))
+
+	// At most ten messages are received at a time
+	batch, err := suite.dequeuer.DequeueBatch(ctx, 20)
+	suite.Require().NoError(err)
+	suite.Len(batch, 10)
+
+	batch, err = suite.dequeuer.DequeueBatch(ctx, 20)
+	suite.Require().NoError(err)
+	suite.Len(batch, 5)
+}
@@ -0,0 +1,139 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/pgvanniekerk/ezQue/api"
+	"strconv"
+)
+
+// maxBatch is the largest number of entries SQS accepts in a single batch request.
+const maxBatch = 10
+
+func NewEnqueuer(client *sqs.Client, queueURL string, groupID string) *Enqueuer {
+	return &Enqueuer{
+		client:   client,
+		queueURL: queueURL,
+		groupID:  groupID,
+	}
+}
+
+// Enqueuer sends messages to a single SQS queue.
+type Enqueuer struct {
+
+	// client is the SQS client. It is safe for concurrent use.
+	client *sqs.Client
+
+	// The URL of the SQS queue that the Enqueuer is bound to.
+	queueURL string
+
+	// groupID is the message group used for messages that do not set
+	// their own GroupID. It is only used with FIFO queues.
+	groupID string
+}
+
+// NewMessage returns a new, empty instance of `Message` that implements the `api.Message` interface.
+func (e *Enqueuer) NewMessage() api.Message[Message] {
+	return &Message{}
+}
+
+// Enqueue sends a message to the bound queue with SendMessage.
+func (e *Enqueuer) Enqueue(ctx context.Context, msg api.Message[Message]) error {
+
+	raw := msg.Raw()
+	_, err := e.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:               aws.String(e.queueURL),
+		MessageBody:            aws.String(raw.Content),
+		MessageAttributes:      messageAttributes(raw.Attributes),
+		MessageGroupId:         e.messageGroupID(raw),
+		MessageDeduplicationId: optionalString(raw.DeduplicationID),
+		DelaySeconds:           raw.DelaySeconds,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	return nil
+}
+
+// EnqueueBatch sends msgs to the bound queue with SendMessageBatch, ten at a time.
+// Entries SQS rejects are reported together in the returned error.
+func (e *Enqueuer) EnqueueBatch(ctx context.Context, msgs []api.Message[Message]) error {
+
+	for start := 0; start < len(msgs); start += maxBatch {
+		end := min(start+maxBatch, len(msgs))
+
+		entries := make([]types.SendMessageBatchRequestEntry, 0, end-start)
+		for i, msg := range msgs[start:end] {
+			raw := msg.Raw()
+			entries = append(entries, types.SendMessageBatchRequestEntry{
+				Id:                     aws.String(strconv.Itoa(start + i)),
+				MessageBody:            aws.String(raw.Content),
+				MessageAttributes:      messageAttributes(raw.Attributes),
+				MessageGroupId:         e.messageGroupID(raw),
+				MessageDeduplicationId: optionalString(raw.DeduplicationID),
+				DelaySeconds:           raw.DelaySeconds,
+			})
+		}
+
+		out, err := e.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(e.queueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enqueue messages: %w", err)
+		}
+
+		if len(out.Failed) > 0 {
+			errs := make([]error, 0, len(out.Failed))
+			for _, failed := range out.Failed {
+				errs = append(errs, fmt.Errorf("message %s: %s: %s", aws.ToString(failed.Id), aws.ToString(failed.Code), aws.ToString(failed.Message)))
+			}
+			return fmt.Errorf("failed to enqueue messages: %w", errors.Join(errs...))
+		}
+	}
+
+	return nil
+}
+
+// messageGroupID returns the message's group, falling back to the Enqueuer's.
+func (e *Enqueuer) messageGroupID(raw Message) *string {
+	if raw.GroupID != "" {
+		return aws.String(raw.GroupID)
+	}
+	return optionalString(e.groupID)
+}
+
+func (e *Enqueuer) Disconnect(_ context.Context) error {
+
+	e.client = nil
+	e.queueURL = ""
+	return nil
+}
+
+// messageAttributes converts attrs to String message attributes.
+func messageAttributes(attrs map[string]string) map[string]types.MessageAttributeValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	values := make(map[string]types.MessageAttributeValue, len(attrs))
+	for key, val := range attrs {
+		values[key] = types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(val),
+		}
+	}
+	return values
+}
+
+// optionalString returns nil for an empty string, so that it is omitted from requests.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
@@ -0,0 +1,75 @@
+package sqs
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/sqs/sqstest"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type EnqueuerTestSuite struct {
+	suite.Suite
+	srv *sqstest.Server
+}
+
+func TestEnqueuerTestSuite(t *testing.T) {
+	suite.Run(t, new(EnqueuerTestSuite))
+}
+
+func (suite *EnqueuerTestSuite) SetupTest() {
+	suite.srv = sqstest.NewServer()
+}
+
+func (suite *EnqueuerTestSuite) TearDownTest() {
+	suite.srv.Close()
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue() {
+	queueURL := suite.srv.CreateQueue("text_msg_queue")
+	enqueuer := NewEnqueuer(newClient(suite.srv), queueURL, "")
+
+	err := enqueuer.Enqueue(context.Background(), &Message{Content: "test message"})
+	suite.NoError(err, "Failed to enqueue message")
+	suite.Equal(1, suite.srv.Len("text_msg_queue"))
+}
+
+func (suite *EnqueuerTestSuite) TestEnqueue_Error() {
+	enqueuer := NewEnqueuer(newClient(suite.srv), suite.srv.URL+"/000000000000/pfft", "")
+
+	err := enqueuer.Enqueue(context.Background(), &Message{Content: "test message"})
+	suite.Error(err, "Expected error during enqueue because the queue does not exist.")
+}
+
+// TestEnqueue_FIFO tests that the Enqueuer's group is used for messages without
+// one, and that FIFO deduplication IDs are passed through.
+func (suite *EnqueuerTestSuite) TestEnqueue_FIFO() {
+	ctx := context.Background()
+	queueURL := suite.srv.CreateQueue("text_msg_queue.fifo")
+	enqueuer := NewEnqueuer(newClient(suite.srv), queueURL, "default-group")
+
+	suite.Require().NoError(enqueuer.Enqueue(ctx, &Message{Content: "first", DeduplicationID: "1"}))
+	suite.Require().NoError(enqueuer.Enqueue(ctx, &Message{Content: "duplicate", DeduplicationID: "1"}))
+	suite.Require().NoError(enqueuer.Enqueue(ctx, &Message{Content: "second", DeduplicationID: "2", GroupID: "other-group"}))
+	suite.Equal(2, suite.srv.Len("text_msg_queue.fifo"), "The duplicate should have been dropped")
+
+	dequeuer := NewDequeuer(newClient(suite.srv), queueURL, 1, 0)
+	batch, err := dequeuer.DequeueBatch(ctx, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(batch, 2)
+	suite.Equal("default-group", batch[0].Message().Raw().GroupID)
+	suite.Equal("other-group", batch[1].Message().Raw().GroupID)
+}
+
+// TestEnqueueBatch_PartialFailure tests that entries rejected by SQS are reported.
+func (suite *EnqueuerTestSuite) TestEnqueueBatch_PartialFailure() {
+	queueURL := suite.srv.CreateQueue("text_msg_queue.fifo")
+	enqueuer := NewEnqueuer(newClient(suite.srv), queueURL, "group")
+
+	err := enqueuer.EnqueueBatch(context.Background(), []api.Message[Message]{
+		&Message{Content: "ok", DeduplicationID: "1"},
+		&Message{Content: "missing deduplication id"},
+	})
+	suite.ErrorContains(err, "message 1")
+	suite.Equal(1, suite.srv.Len("text_msg_queue.fifo"))
+}
@@ -0,0 +1,81 @@
+package sqs
+
+import (
+	"sort"
+	"time"
+)
+
+// Message is an SQS message as seen by ezQue. Attributes are sent as String
+// message attributes. GroupID and DeduplicationID only apply to FIFO queues.
+// ID, ReceiptHandle, ReceiveCount and SentAt are populated on Dequeue and are
+// ignored on Enqueue.
+type Message struct {
+	ID              string
+	Content         string
+	Attributes      map[string]string
+	GroupID         string
+	DeduplicationID string
+	DelaySeconds    int32
+	ReceiptHandle   string
+	ReceiveCount    int
+	SentAt          time.Time
+}
+
+func (m *Message) Raw() Message {
+	return *m
+}
+
+func (m *Message) Text() string {
+	return m.Content
+}
+
+func (m *Message) SetRaw(raw Message) {
+	*m = raw
+}
+
+func (m *Message) SetText(msg string) {
+	m.Content = msg
+}
+
+// GetIdempotencyKey returns the deduplication ID, implementing api.Idempotent.
+func (m *Message) GetIdempotencyKey() string {
+	return m.DeduplicationID
+}
+
+// SetIdempotencyKey sets the deduplication ID, implementing api.Idempotent. SQS only
+// deduplicates messages sent to FIFO queues, within its five minute deduplication
+// interval.
+func (m *Message) SetIdempotencyKey(key string) {
+	m.DeduplicationID = key
+}
+
+// GetProperty returns the named message attribute, implementing api.Properties.
+func (m *Message) GetProperty(key string) string {
+	return m.Attributes[key]
+}
+
+// SetProperty sets the named message attribute, implementing api.Properties. SQS
+// allows at most ten message attributes per message.
+func (m *Message) SetProperty(key string, value string) {
+	if m.Attributes == nil {
+		m.Attributes = make(map[string]string)
+	}
+	m.Attributes[key] = value
+}
+
+// PropertyKeys returns the names of the message attributes, implementing
+// api.Properties.
+func (m *Message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.Attributes))
+	for key := range m.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetEnqueuedAt returns when SQS received the message, implementing
+// api.Timestamped.
+func (m *Message) GetEnqueuedAt() time.Time {
+	return m.SentAt
+}
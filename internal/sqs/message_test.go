@@ -0,0 +1,57 @@
+package sqs
+
+import (
+	"testing"
+
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaw(t *testing.T) {
+	message := &Message{
+		ID:         "id",
+		Content:    "testContent",
+		Attributes: map[string]string{"h": "v"},
+		GroupID:    "group",
+	}
+
+	raw := message.Raw()
+
+	// check if the properties of the raw message match those of the original message
+	require.Equal(t, message.ID, raw.ID, "Raw ID does not match the original message's ID")
+	require.Equal(t, message.Content, raw.Content, "Raw Content does not match the original message's Content")
+	require.Equal(t, message.Attributes, raw.Attributes, "Raw Attributes do not match the original message's Attributes")
+	require.Equal(t, message.GroupID, raw.GroupID, "Raw GroupID does not match the original message's GroupID")
+}
+
+func TestText(t *testing.T) {
+	const content = "testContent"
+
+	message := &Message{}
+	message.SetText(content)
+
+	// Text() should return the Content property of the message
+	require.Equal(t, content, message.Text(), "Text does not return the correct message Content")
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	message := &Message{}
+	message.SetIdempotencyKey("order-1")
+
+	// the idempotency key is sent as the deduplication ID
+	require.Equal(t, "order-1", message.DeduplicationID)
+	require.Equal(t, "order-1", message.GetIdempotencyKey())
+}
+
+func TestProperties(t *testing.T) {
+	var properties api.Properties = &Message{}
+	properties.SetProperty("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	properties.SetProperty("count", "3")
+
+	// the api.Properties methods should map onto the message attributes
+	message := properties.(*Message)
+	require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", message.Attributes["traceparent"])
+	require.Equal(t, "3", properties.GetProperty("count"))
+	require.Equal(t, "", properties.GetProperty("missing"))
+	require.Equal(t, []string{"count", "traceparent"}, properties.PropertyKeys())
+}
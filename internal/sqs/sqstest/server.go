@@ -0,0 +1,363 @@
+// Package sqstest provides an in-memory stand-in for Amazon SQS, served over the SQS JSON
+// protocol, for use in tests. It implements the subset of the API used by ezQue: GetQueueUrl,
+// SendMessage, SendMessageBatch, ReceiveMessage with long polling, DeleteMessage and
+// ChangeMessageVisibility, including FIFO message groups and deduplication.
+package sqstest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultVisibilityTimeout matches the SQS default of 30 seconds.
+const defaultVisibilityTimeout = 30 * time.Second
+
+// Server is an in-memory SQS stand-in listening on a local HTTP address.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	queues map[string]*queue
+	nextID int
+}
+
+type queue struct {
+	name     string
+	fifo     bool
+	messages []*message
+	dedupIDs map[string]string
+}
+
+type message struct {
+	id            string
+	body          string
+	attributes    map[string]attributeValue
+	groupID       string
+	dedupID       string
+	sent          time.Time
+	receiveCount  int
+	receiptHandle string
+	visibleAt     time.Time
+}
+
+type attributeValue struct {
+	DataType    string `json:"DataType"`
+	StringValue string `json:"StringValue,omitempty"`
+}
+
+// NewServer starts a Server. It should be closed when no longer needed.
+func NewServer() *Server {
+	s := &Server{queues: make(map[string]*queue)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// CreateQueue creates a queue and returns its URL. Names ending in ".fifo"
+// create FIFO queues.
+func (s *Server) CreateQueue(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues[name] = &queue{
+		name:     name,
+		fifo:     strings.HasSuffix(name, ".fifo"),
+		dedupIDs: make(map[string]string),
+	}
+	return s.queueURL(name)
+}
+
+// Len returns the number of messages in the named queue, including in-flight ones.
+func (s *Server) Len(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queues[name].messages)
+}
+
+func (s *Server) queueURL(name string) string {
+	return s.URL + "/000000000000/" + name
+}
+
+// handle dispatches a request on its X-Amz-Target header.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	var req map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "InvalidParameterValue", err.Error())
+		return
+	}
+
+	switch strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSQS.") {
+	case "GetQueueUrl":
+		s.getQueueURL(w, req)
+	case "SendMessage":
+		s.sendMessage(w, req)
+	case "SendMessageBatch":
+		s.sendMessageBatch(w, req)
+	case "ReceiveMessage":
+		s.receiveMessage(w, r, req)
+	case "DeleteMessage":
+		s.deleteMessage(w, req)
+	case "ChangeMessageVisibility":
+		s.changeMessageVisibility(w, req)
+	default:
+		writeError(w, "UnsupportedOperation", r.Header.Get("X-Amz-Target"))
+	}
+}
+
+func (s *Server) getQueueURL(w http.ResponseWriter, req map[string]json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := str(req["QueueName"])
+	if _, ok := s.queues[name]; !ok {
+		writeError(w, "QueueDoesNotExist", "The specified queue does not exist.")
+		return
+	}
+	writeJSON(w, map[string]string{"QueueUrl": s.queueURL(name)})
+}
+
+// entry holds the fields shared by SendMessage and SendMessageBatch entries.
+type entry struct {
+	ID                     string                    `json:"Id"`
+	MessageBody            string                    `json:"MessageBody"`
+	MessageAttributes      map[string]attributeValue `json:"MessageAttributes"`
+	MessageGroupID         string                    `json:"MessageGroupId"`
+	MessageDeduplicationID string                    `json:"MessageDeduplicationId"`
+}
+
+func (s *Server) sendMessage(w http.ResponseWriter, req map[string]json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.lookup(w, req)
+	if !ok {
+		return
+	}
+	var e entry
+	_ = json.Unmarshal(mustJSON(req), &e)
+
+	id, code, msg := s.send(q, e)
+	if code != "" {
+		writeError(w, code, msg)
+		return
+	}
+	writeJSON(w, map[string]string{"MessageId": id, "MD5OfMessageBody": md5Hex(e.MessageBody)})
+}
+
+func (s *Server) sendMessageBatch(w http.ResponseWriter, req map[string]json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.lookup(w, req)
+	if !ok {
+		return
+	}
+	var entries []entry
+	_ = json.Unmarshal(req["Entries"], &entries)
+
+	successful := []map[string]string{}
+	failed := []map[string]interface{}{}
+	for _, e := range entries {
+		id, code, msg := s.send(q, e)
+		if code != "" {
+			failed = append(failed, map[string]interface{}{"Id": e.ID, "Code": code, "Message": msg, "SenderFault": true})
+			continue
+		}
+		successful = append(successful, map[string]string{"Id": e.ID, "MessageId": id, "MD5OfMessageBody": md5Hex(e.MessageBody)})
+	}
+	writeJSON(w, map[string]interface{}{"Successful": successful, "Failed": failed})
+}
+
+// send appends a message to q, returning its ID or an error code and message.
+func (s *Server) send(q *queue, e entry) (string, string, string) {
+	if q.fifo {
+		if e.MessageGroupID == "" {
+			return "", "MissingParameter", "The request must contain the parameter MessageGroupId."
+		}
+		if e.MessageDeduplicationID == "" {
+			return "", "InvalidParameterValue", "The queue should either have ContentBasedDeduplication enabled or MessageDeduplicationId provided explicitly"
+		}
+		if id, ok := q.dedupIDs[e.MessageDeduplicationID]; ok {
+			return id, "", ""
+		}
+	}
+
+	s.nextID++
+	m := &message{
+		id:         fmt.Sprintf("00000000-0000-0000-0000-%012d", s.nextID),
+		body:       e.MessageBody,
+		attributes: e.MessageAttributes,
+		groupID:    e.MessageGroupID,
+		dedupID:    e.MessageDeduplicationID,
+		sent:       time.Now(),
+	}
+	q.messages = append(q.messages, m)
+	if q.fifo {
+		q.dedupIDs[e.MessageDeduplicationID] = m.id
+	}
+	return m.id, "", ""
+}
+
+func (s *Server) receiveMessage(w http.ResponseWriter, r *http.Request, req map[string]json.RawMessage) {
+	maxMessages := num(req["MaxNumberOfMessages"], 1)
+	wait := time.Duration(num(req["WaitTimeSeconds"], 0)) * time.Second
+	visibility := defaultVisibilityTimeout
+	if _, ok := req["VisibilityTimeout"]; ok {
+		visibility = time.Duration(num(req["VisibilityTimeout"], 0)) * time.Second
+	}
+
+	// Long poll until a message is visible, the wait time has passed or the client gave up
+	deadline := time.Now().Add(wait)
+	for {
+		s.mu.Lock()
+		q, ok := s.lookup(w, req)
+		if !ok {
+			s.mu.Unlock()
+			return
+		}
+		received := s.receive(q, maxMessages, visibility)
+		s.mu.Unlock()
+
+		if len(received) > 0 || !time.Now().Before(deadline) || r.Context().Err() != nil {
+			writeJSON(w, map[string]interface{}{"Messages": received})
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// receive makes up to maxMessages visible messages of q in-flight. On FIFO queues
+// a message group is skipped while it has a message in flight from an earlier receive.
+func (s *Server) receive(q *queue, maxMessages int, visibility time.Duration) []map[string]interface{} {
+	now := time.Now()
+	blockedGroups := make(map[string]bool)
+	received := []map[string]interface{}{}
+
+	for _, m := range q.messages {
+		if len(received) == maxMessages {
+			break
+		}
+		if q.fifo && blockedGroups[m.groupID] {
+			continue
+		}
+		if m.visibleAt.After(now) {
+			blockedGroups[m.groupID] = true
+			continue
+		}
+
+		m.receiveCount++
+		m.receiptHandle = fmt.Sprintf("%s-%d", m.id, m.receiveCount)
+		m.visibleAt = now.Add(visibility)
+
+		attrs := map[string]string{
+			"ApproximateReceiveCount": strconv.Itoa(m.receiveCount),
+			"SentTimestamp":           strconv.FormatInt(m.sent.UnixMilli(), 10),
+		}
+		if q.fifo {
+			attrs["MessageGroupId"] = m.groupID
+			attrs["MessageDeduplicationId"] = m.dedupID
+		}
+		received = append(received, map[string]interface{}{
+			"MessageId":         m.id,
+			"ReceiptHandle":     m.receiptHandle,
+			"Body":              m.body,
+			"MD5OfBody":         md5Hex(m.body),
+			"Attributes":        attrs,
+			"MessageAttributes": m.attributes,
+		})
+	}
+	return received
+}
+
+func (s *Server) deleteMessage(w http.ResponseWriter, req map[string]json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.lookup(w, req)
+	if !ok {
+		return
+	}
+	handle := str(req["ReceiptHandle"])
+	for i, m := range q.messages {
+		if m.receiptHandle == handle {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			writeJSON(w, map[string]string{})
+			return
+		}
+	}
+	writeError(w, "ReceiptHandleIsInvalid", "The input receipt handle is invalid.")
+}
+
+func (s *Server) changeMessageVisibility(w http.ResponseWriter, req map[string]json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.lookup(w, req)
+	if !ok {
+		return
+	}
+	handle := str(req["ReceiptHandle"])
+	for _, m := range q.messages {
+		if m.receiptHandle == handle {
+			m.visibleAt = time.Now().Add(time.Duration(num(req["VisibilityTimeout"], 0)) * time.Second)
+			writeJSON(w, map[string]string{})
+			return
+		}
+	}
+	writeError(w, "ReceiptHandleIsInvalid", "The input receipt handle is invalid.")
+}
+
+// lookup returns the queue addressed by the request's QueueUrl, writing an
+// error response if it does not exist.
+func (s *Server) lookup(w http.ResponseWriter, req map[string]json.RawMessage) (*queue, bool) {
+	url := str(req["QueueUrl"])
+	q, ok := s.queues[url[strings.LastIndex(url, "/")+1:]]
+	if !ok {
+		writeError(w, "QueueDoesNotExist", "The specified queue does not exist.")
+	}
+	return q, ok
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code string, msg string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"__type": "com.amazonaws.sqs#" + code, "message": msg})
+}
+
+func str(raw json.RawMessage) string {
+	var s string
+	_ = json.Unmarshal(raw, &s)
+	return s
+}
+
+func num(raw json.RawMessage, def int) int {
+	if raw == nil {
+		return def
+	}
+	var n int
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return def
+	}
+	return n
+}
+
+func mustJSON(v interface{}) []byte {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,229 @@
+package jetstream
+
+import (
+	"context"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	natsjs "github.com/nats-io/nats.go/jetstream"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/jetstream"
+	"strings"
+	"time"
+)
+
+// defaultFetchWait is used when no WithFetchWait option is given.
+const defaultFetchWait = 5 * time.Second
+
+// NatsJetStream is provided as a queueConnector to ezQueue.Connect method, to connect to a NATS JetStream stream.
+func NatsJetStream(options OptionFunc) (api.Enqueuer[jetstream.Message], api.Dequeuer[jetstream.Message], error) {
+	return connect(options)
+}
+
+// connect establishes both enqueue and dequeue connections.
+func connect(opts OptionFunc) (api.Enqueuer[jetstream.Message], api.Dequeuer[jetstream.Message], error) {
+
+	// Initialise Enqueuer
+	enq, err := connectEnqueue(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialise Dequeuer
+	deq, err := connectDequeue(opts)
+	if err != nil {
+		_ = enq.Disconnect(context.Background())
+		return nil, nil, err
+	}
+
+	return enq, deq, nil
+}
+
+// connectEnqueue establishes an enqueue connection.
+func connectEnqueue(options OptionFunc) (api.Enqueuer[jetstream.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("jetstream: options is nil")
+	}
+	opts := options()
+
+	// Validate subject
+	if opts.subject == "" {
+		return nil, fmt.Errorf("jetstream: subject is empty")
+	}
+
+	connOpts := buildConnOptions(opts)
+
+	// connect to the server
+	conn, err := nats.Connect(strings.Join(connOpts.urls, ","), connOpts.natsOpts...)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := natsjs.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	enq := jetstream.NewEnqueuer(conn, stream, opts.subject)
+	return enq, nil
+}
+
+// connectDequeue establishes a dequeue connection, creating the durable pull
+// consumer if it does not exist yet.
+func connectDequeue(options OptionFunc) (api.Dequeuer[jetstream.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("jetstream: options is nil")
+	}
+	opts := options()
+
+	// Validate subject, stream and durable
+	if opts.subject == "" {
+		return nil, fmt.Errorf("jetstream: subject is empty")
+	}
+	if opts.stream == "" {
+		return nil, fmt.Errorf("jetstream: stream is empty")
+	}
+	if opts.durable == "" {
+		return nil, fmt.Errorf("jetstream: durable is empty")
+	}
+
+	connOpts := buildConnOptions(opts)
+
+	// connect to the server
+	conn, err := nats.Connect(strings.Join(connOpts.urls, ","), connOpts.natsOpts...)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := natsjs.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// bind to the durable consumer
+	consumer, err := stream.CreateOrUpdateConsumer(context.Background(), opts.stream, natsjs.ConsumerConfig{
+		Durable:       opts.durable,
+		FilterSubject: opts.subject,
+		AckPolicy:     natsjs.AckExplicitPolicy,
+		AckWait:       connOpts.ackWait,
+		MaxDeliver:    connOpts.maxDeliver,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	deq := jetstream.NewDequeuer(conn, consumer, connOpts.fetchWait, connOpts.nakDelay)
+	return deq, nil
+}
+
+// buildConnOptions applies the ConnOptionFuncs over the defaults.
+func buildConnOptions(opts Options) *connOptions {
+	connOpts := &connOptions{
+		urls:      []string{nats.DefaultURL},
+		fetchWait: defaultFetchWait,
+	}
+	for _, opt := range opts.connOpts {
+		opt(connOpts)
+	}
+	return connOpts
+}
+
+// Options struct holds connection options, the subject, the stream capturing
+// it and the name of the durable consumer.
+type Options struct {
+	connOpts []ConnOptionFunc
+	subject  string
+	stream   string
+	durable  string
+}
+
+// OptionFunc is a function type that returns Options.
+type OptionFunc func() Options
+
+// Subject returns an OptionFunc that holds connection options, the subject messages are
+// enqueued to, the stream that captures that subject and the durable consumer that
+// Dequeue pulls from.
+func Subject(subject string, stream string, durable string, connOpts ...ConnOptionFunc) OptionFunc {
+	return func() Options {
+		return Options{
+			connOpts: connOpts,
+			subject:  subject,
+			stream:   stream,
+			durable:  durable,
+		}
+	}
+}
+
+// ConnOptionFunc is a function type to set connOptions.
+type ConnOptionFunc func(*connOptions)
+
+// connOptions struct holds the information required for creating connections and consumers.
+type connOptions struct {
+	urls       []string
+	natsOpts   []nats.Option
+	fetchWait  time.Duration
+	nakDelay   time.Duration
+	ackWait    time.Duration
+	maxDeliver int
+}
+
+// LocatedAt sets the NATS server URLs for ConnOptionFunc.
+func LocatedAt(urls ...string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.urls = urls
+	}
+}
+
+// AuthenticatedWith sets username and password for ConnOptionFunc.
+func AuthenticatedWith(username string, password string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.natsOpts = append(opts.natsOpts, nats.UserInfo(username, password))
+	}
+}
+
+// UsingCredentials authenticates with a NATS credentials file for ConnOptionFunc.
+func UsingCredentials(file string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.natsOpts = append(opts.natsOpts, nats.UserCredentials(file))
+	}
+}
+
+// WithNATSOptions appends options that are passed as-is to nats.Connect.
+func WithNATSOptions(natsOpts ...nats.Option) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.natsOpts = append(opts.natsOpts, natsOpts...)
+	}
+}
+
+// WithFetchWait sets how long a single pull request waits for a message before
+// a new one is issued.
+func WithFetchWait(wait time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.fetchWait = wait
+	}
+}
+
+// WithNakDelay sets how long the server waits before redelivering a NAcked message.
+func WithNakDelay(delay time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.nakDelay = delay
+	}
+}
+
+// WithAckWait sets how long the server waits for an Ack before redelivering a message.
+func WithAckWait(wait time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.ackWait = wait
+	}
+}
+
+// WithMaxDeliver limits the number of times a message is delivered.
+func WithMaxDeliver(maxDeliver int) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.maxDeliver = maxDeliver
+	}
+}
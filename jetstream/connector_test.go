@@ -0,0 +1,124 @@
+package jetstream
+
+import (
+	"context"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	natsjs "github.com/nats-io/nats.go/jetstream"
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+// TestConnector ensures that NatsJetStream can be provided
+// to the ezQue.Connect function.
+func TestConnector(t *testing.T) {
+	_, _ = ezQue.Connect(NatsJetStream, nil)
+}
+
+// TestConnOptions ensures that the ConnOptionFuncs correctly set the config values
+func TestConnOptions(t *testing.T) {
+	opts := buildConnOptions(Subject("subject", "stream", "durable",
+		LocatedAt("nats://a:4222", "nats://b:4222"),
+		AuthenticatedWith("user", "pass"),
+		WithFetchWait(time.Second),
+		WithNakDelay(time.Minute),
+		WithAckWait(time.Hour),
+		WithMaxDeliver(3),
+	)())
+
+	require.Equal(t, []string{"nats://a:4222", "nats://b:4222"}, opts.urls)
+	require.Len(t, opts.natsOpts, 1)
+	require.Equal(t, time.Second, opts.fetchWait)
+	require.Equal(t, time.Minute, opts.nakDelay)
+	require.Equal(t, time.Hour, opts.ackWait)
+	require.Equal(t, 3, opts.maxDeliver)
+}
+
+//
+// connect
+//
+
+type ConnectTestSuite struct {
+	suite.Suite
+	srv *server.Server
+}
+
+func TestConnectTestSuite(t *testing.T) {
+	suite.Run(t, new(ConnectTestSuite))
+}
+
+func (suite *ConnectTestSuite) SetupSuite() {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  suite.T().TempDir(),
+	})
+	suite.Require().NoError(err)
+	go srv.Start()
+	suite.Require().True(srv.ReadyForConnections(5 * time.Second))
+	suite.srv = srv
+
+	conn, err := nats.Connect(srv.ClientURL())
+	suite.Require().NoError(err)
+	defer conn.Close()
+	stream, err := natsjs.New(conn)
+	suite.Require().NoError(err)
+	_, err = stream.CreateStream(context.Background(), natsjs.StreamConfig{Name: "ORDERS", Subjects: []string{"orders.>"}})
+	suite.Require().NoError(err)
+}
+
+func (suite *ConnectTestSuite) TearDownSuite() {
+	suite.srv.Shutdown()
+}
+
+func (suite *ConnectTestSuite) Test_FailOnNilOptions() {
+	_, _, err := connect(nil)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptySubject() {
+	_, _, err := connect(Subject("", "ORDERS", "durable", LocatedAt(suite.srv.ClientURL())))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyStream() {
+	_, _, err := connect(Subject("orders.new", "", "durable", LocatedAt(suite.srv.ClientURL())))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyDurable() {
+	_, _, err := connect(Subject("orders.new", "ORDERS", "", LocatedAt(suite.srv.ClientURL())))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnUnknownStream() {
+	_, _, err := connect(Subject("orders.new", "NOPE", "durable", LocatedAt(suite.srv.ClientURL())))
+	assert.NotNil(suite.T(), err)
+}
+
+// Test_EnqueueDequeue tests a round trip through a Queue connected with NatsJetStream.
+func (suite *ConnectTestSuite) Test_EnqueueDequeue() {
+	q, err := ezQue.Connect(NatsJetStream, Subject("orders.new", "ORDERS", "processor",
+		LocatedAt(suite.srv.ClientURL()),
+		WithFetchWait(100*time.Millisecond),
+	))
+	suite.Require().NoError(err)
+	defer func() { suite.NoError(q.Disconnect(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg := q.NewMessage()
+	msg.SetText("test message")
+	suite.Require().NoError(q.Enqueue(ctx, msg))
+
+	deqMsg, err := q.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.NoError(deqMsg.Ack(ctx))
+}
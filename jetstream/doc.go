@@ -0,0 +1,16 @@
+// Package jetstream provides Go functions and types for working with NATS JetStream through the
+// ezQue Queue abstraction.
+//
+// Central to the package is the NatsJetStream function. This function takes in an OptionFunc parameter
+// for customizing the connection options and returns Enqueuer and Dequeuer instances associated
+// with the specified subject.
+//
+// Enqueue publishes to the subject and waits for the stream capturing it to store the message. Dequeue
+// pulls from a durable pull consumer, which is created on connect if it does not exist, one message per
+// fetch. Ack acknowledges the message and waits for the server to confirm it, and NAck naks it, with the
+// delay set by WithNakDelay if any. Message headers are carried as NATS headers.
+//
+// The stream itself is not created by this package and must exist before connecting.
+//
+// Note: This package relies on "ezQue/api", "ezQue/internal/jetstream" and "github.com/nats-io/nats.go".
+package jetstream
@@ -0,0 +1,195 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/kafka"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+)
+
+// ApacheKafka is provided as a queueConnector to ezQueue.Connect method, to connect to an Apache Kafka topic.
+func ApacheKafka(options OptionFunc) (api.Enqueuer[kafka.Message], api.Dequeuer[kafka.Message], error) {
+	return connect(options)
+}
+
+// connect establishes both producer and consumer connections.
+func connect(opts OptionFunc) (api.Enqueuer[kafka.Message], api.Dequeuer[kafka.Message], error) {
+
+	// Initialise Enqueuer
+	enq, err := connectEnqueue(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialise Dequeuer
+	deq, err := connectDequeue(opts)
+	if err != nil {
+		_ = enq.Disconnect(context.Background())
+		return nil, nil, err
+	}
+
+	return enq, deq, nil
+}
+
+// connectEnqueue establishes a producer connection.
+func connectEnqueue(options OptionFunc) (api.Enqueuer[kafka.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("kafka: options is nil")
+	}
+	opts := options()
+
+	// Validate topic
+	if opts.topic == "" {
+		return nil, fmt.Errorf("kafka: topic is empty")
+	}
+
+	clientOpts, err := buildClientOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	clientOpts = append(clientOpts, kgo.DefaultProduceTopic(opts.topic))
+
+	// connect to the cluster
+	client, err := kgo.NewClient(clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	err = client.Ping(context.Background())
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	enq := kafka.NewEnqueuer(client, opts.topic)
+	return enq, nil
+}
+
+// connectDequeue establishes a consumer group connection.
+func connectDequeue(options OptionFunc) (api.Dequeuer[kafka.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("kafka: options is nil")
+	}
+	opts := options()
+
+	// Validate topic
+	if opts.topic == "" {
+		return nil, fmt.Errorf("kafka: topic is empty")
+	}
+
+	clientOpts, err := buildClientOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate consumer group
+	if opts.group == "" {
+		return nil, fmt.Errorf("kafka: consumer group is empty")
+	}
+	clientOpts = append(clientOpts,
+		kgo.ConsumeTopics(opts.topic),
+		kgo.ConsumerGroup(opts.group),
+		kgo.DisableAutoCommit(),
+	)
+
+	// connect to the cluster
+	client, err := kgo.NewClient(clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	err = client.Ping(context.Background())
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	deq := kafka.NewDequeuer(client, opts.topic)
+	return deq, nil
+}
+
+// buildClientOptions applies the ClientOptionFuncs and translates the result
+// into options for the underlying Kafka client.
+func buildClientOptions(opts Options) ([]kgo.Opt, error) {
+
+	clientOpts := &clientOptions{}
+	for _, opt := range opts.clientOpts {
+		opt(clientOpts)
+	}
+
+	// Validate brokers
+	if len(clientOpts.brokers) == 0 {
+		return nil, fmt.Errorf("kafka: no brokers provided")
+	}
+
+	kgoOpts := []kgo.Opt{kgo.SeedBrokers(clientOpts.brokers...)}
+	if clientOpts.username != "" {
+		kgoOpts = append(kgoOpts, kgo.SASL(plain.Auth{
+			User: clientOpts.username,
+			Pass: clientOpts.password,
+		}.AsMechanism()))
+	}
+	kgoOpts = append(kgoOpts, clientOpts.extra...)
+
+	return kgoOpts, nil
+}
+
+// Options struct holds client options, the topic and the consumer group.
+type Options struct {
+	clientOpts []ClientOptionFunc
+	topic      string
+	group      string
+}
+
+// OptionFunc is a function type that returns Options.
+type OptionFunc func() Options
+
+// Topic returns an OptionFunc that holds client options, a topic and the consumer
+// group that Dequeue consumes the topic in.
+func Topic(topic string, group string, clientOpts ...ClientOptionFunc) OptionFunc {
+	return func() Options {
+		return Options{
+			clientOpts: clientOpts,
+			topic:      topic,
+			group:      group,
+		}
+	}
+}
+
+// ClientOptionFunc is a function type to set clientOptions.
+type ClientOptionFunc func(*clientOptions)
+
+// clientOptions struct holds the information required for creating Kafka clients.
+type clientOptions struct {
+	brokers  []string
+	username string
+	password string
+	extra    []kgo.Opt
+}
+
+// LocatedAt sets the seed brokers, given as host:port, for ClientOptionFunc.
+func LocatedAt(brokers ...string) ClientOptionFunc {
+	return func(opts *clientOptions) {
+		opts.brokers = append(opts.brokers, brokers...)
+	}
+}
+
+// AuthenticatedWith sets the SASL/PLAIN username and password for ClientOptionFunc.
+func AuthenticatedWith(username string, password string) ClientOptionFunc {
+	return func(opts *clientOptions) {
+		opts.username = username
+		opts.password = password
+	}
+}
+
+// WithClientOptions appends options that are passed as-is to the underlying
+// franz-go client, e.g. for TLS or a custom partitioner.
+func WithClientOptions(kgoOpts ...kgo.Opt) ClientOptionFunc {
+	return func(opts *clientOptions) {
+		opts.extra = append(opts.extra, kgoOpts...)
+	}
+}
@@ -0,0 +1,113 @@
+package kafka
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"testing"
+	"time"
+)
+
+// TestConnector ensures that ApacheKafka can be provided
+// to the ezQue.Connect function.
+func TestConnector(t *testing.T) {
+	_, _ = ezQue.Connect(ApacheKafka, nil)
+}
+
+// TestLocatedAt ensures that it correctly sets the config values
+func TestLocatedAt(t *testing.T) {
+	opts := &clientOptions{}
+	LocatedAt("broker1:9092", "broker2:9092")(opts)
+
+	require.Equal(t, []string{"broker1:9092", "broker2:9092"}, opts.brokers, "The brokers in clientOptions did not match the expected value")
+}
+
+// TestAuthenticatedWith ensures that it correctly sets the config values
+func TestAuthenticatedWith(t *testing.T) {
+	const (
+		username = "testUser"
+		password = "testPassword"
+	)
+
+	opts := &clientOptions{}
+	AuthenticatedWith(username, password)(opts)
+
+	require.Equal(t, username, opts.username, "The username in clientOptions did not match the expected value")
+	require.Equal(t, password, opts.password, "The password in clientOptions did not match the expected value")
+}
+
+// TestWithClientOptions ensures that it correctly sets the config values
+func TestWithClientOptions(t *testing.T) {
+	opts := &clientOptions{}
+	WithClientOptions(kgo.ClientID("a"), kgo.ClientID("b"))(opts)
+
+	require.Len(t, opts.extra, 2, "The extra client options were not all appended")
+}
+
+//
+// connect
+//
+
+type ConnectTestSuite struct {
+	suite.Suite
+	cluster *kfake.Cluster
+}
+
+func TestConnectTestSuite(t *testing.T) {
+	suite.Run(t, new(ConnectTestSuite))
+}
+
+func (suite *ConnectTestSuite) SetupSuite() {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(3, "test_topic"))
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	suite.cluster = cluster
+}
+
+func (suite *ConnectTestSuite) TearDownSuite() {
+	suite.cluster.Close()
+}
+
+func (suite *ConnectTestSuite) Test_FailOnNilOptions() {
+	_, _, err := connect(nil)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyTopic() {
+	_, _, err := connect(Topic("", "test_group", LocatedAt(suite.cluster.ListenAddrs()...)))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyGroup() {
+	_, _, err := connect(Topic("test_topic", "", LocatedAt(suite.cluster.ListenAddrs()...)))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnNoBrokers() {
+	_, _, err := connect(Topic("test_topic", "test_group"))
+	assert.NotNil(suite.T(), err)
+}
+
+// Test_EnqueueDequeue tests a round trip through a Queue connected with ApacheKafka.
+func (suite *ConnectTestSuite) Test_EnqueueDequeue() {
+	q, err := ezQue.Connect(ApacheKafka, Topic("test_topic", "test_group", LocatedAt(suite.cluster.ListenAddrs()...)))
+	suite.Require().NoError(err)
+	defer func() { suite.NoError(q.Disconnect(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	msg := q.NewMessage()
+	msg.SetText("test message")
+	suite.Require().NoError(q.Enqueue(ctx, msg))
+
+	deqMsg, err := q.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.NoError(deqMsg.Ack(ctx))
+}
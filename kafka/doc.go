@@ -0,0 +1,23 @@
+// Package kafka provides Go functions and types for working with Apache Kafka topics through
+// the ezQue Queue abstraction.
+//
+// Central to the package is the ApacheKafka function. This function takes in an OptionFunc parameter
+// for customizing the connection options and returns Enqueuer and Dequeuer instances associated
+// with the specified topic.
+//
+// A Queue maps onto a single topic. Enqueue produces a record to the topic, using the message Key to
+// select its partition, and Dequeue consumes the topic as a member of the consumer group given to Topic.
+// Every consumer connected with the same group shares the topic's partitions between them, so a message
+// is delivered to one consumer in the group.
+//
+// Kafka only orders records within a partition. Messages enqueued with the same Key are therefore
+// dequeued in the order they were enqueued, while messages with different keys carry no ordering
+// guarantee relative to each other. Messages without a Key are spread across partitions.
+//
+// Ack commits the message's offset to the consumer group. Because offsets are committed per partition,
+// acknowledging a message also acknowledges all earlier messages in its partition: messages should be
+// acknowledged in the order they were dequeued. NAck seeks the partition back to the message, so it and
+// every later message in the same partition are delivered again.
+//
+// Note: This package relies on "ezQue/api", "ezQue/internal/kafka" and "github.com/twmb/franz-go".
+package kafka
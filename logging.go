@@ -0,0 +1,140 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	"log/slog"
+	"time"
+)
+
+// defaultSlowThreshold is used when WithLogger is given no slow threshold.
+const defaultSlowThreshold = time.Second
+
+// WithLogger logs the connection lifecycle of the Queue, and the outcome of every
+// enqueue, dequeue, Ack and NAck, to logger. The levels are quiet by default:
+//
+//   - Info: the Queue connected or disconnected,
+//   - Warn: an enqueue, Ack or NAck took longer than slowThreshold (which defaults to
+//     a second), or an enqueue or dequeue failed,
+//   - Error: the Queue failed to connect or disconnect, or an Ack or NAck failed,
+//   - Debug: every message enqueued, dequeued, acknowledged and negatively acknowledged.
+//
+// Every record carries queueName as its queue attribute, unless it is empty, and
+// messages are logged with their message_id if they implement api.Identified. Pass
+// WithLogger before other interceptors, so that the failures they return are logged
+// too.
+func WithLogger[R any](logger *slog.Logger, queueName string, slowThreshold time.Duration) ConnectOption[R] {
+
+	if slowThreshold <= 0 {
+		slowThreshold = defaultSlowThreshold
+	}
+	if queueName != "" {
+		logger = logger.With(slog.String("queue", queueName))
+	}
+	l := &queueLogger[R]{
+		logger:        logger,
+		slowThreshold: slowThreshold,
+	}
+
+	return func(q *queue[R]) {
+		q.logger = logger
+		WithInterceptors(Interceptors[R]{
+			Enqueue: l.enqueue,
+			Dequeue: l.dequeue,
+			Ack:     l.ack,
+		})(q)
+	}
+}
+
+// queueLogger logs the operations on a Queue.
+type queueLogger[R any] struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+}
+
+func (l *queueLogger[R]) enqueue(ctx context.Context, msg api.Message[R], next EnqueueFunc[R]) error {
+
+	start := time.Now()
+	err := next(ctx, msg)
+	elapsed := time.Since(start)
+
+	attrs := append(messageAttrs[R](msg), slog.Duration("duration", elapsed))
+	switch {
+	case err != nil:
+		l.logger.LogAttrs(ctx, slog.LevelWarn, "enqueue failed", append(attrs, errorAttrs(err)...)...)
+	case elapsed > l.slowThreshold:
+		l.logger.LogAttrs(ctx, slog.LevelWarn, "slow enqueue", attrs...)
+	default:
+		l.logger.LogAttrs(ctx, slog.LevelDebug, "message enqueued", attrs...)
+	}
+
+	return err
+}
+
+func (l *queueLogger[R]) dequeue(ctx context.Context, next DequeueFunc[R]) (api.DequeueMessage[R], error) {
+
+	start := time.Now()
+	msg, err := next(ctx)
+	elapsed := time.Since(start)
+
+	if err != nil {
+
+		// A dequeue ended by its context is part of normal operation
+		level := slog.LevelWarn
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			level = slog.LevelDebug
+		}
+		l.logger.LogAttrs(ctx, level, "dequeue failed", append(errorAttrs(err), slog.Duration("duration", elapsed))...)
+		return nil, err
+	}
+
+	l.logger.LogAttrs(ctx, slog.LevelDebug, "message dequeued",
+		append(messageAttrs[R](msg.Message()), slog.Duration("duration", elapsed))...)
+
+	return msg, nil
+}
+
+func (l *queueLogger[R]) ack(ctx context.Context, msg api.DequeueMessage[R], ack bool, next AckFunc[R]) error {
+
+	start := time.Now()
+	err := next(ctx, msg)
+	elapsed := time.Since(start)
+
+	operation, done := "nack", "message negatively acknowledged"
+	if ack {
+		operation, done = "ack", "message acknowledged"
+	}
+
+	attrs := append(messageAttrs[R](msg.Message()), slog.Duration("duration", elapsed))
+	switch {
+	case err != nil:
+		l.logger.LogAttrs(ctx, slog.LevelError, operation+" failed", append(attrs, errorAttrs(err)...)...)
+	case elapsed > l.slowThreshold:
+		l.logger.LogAttrs(ctx, slog.LevelWarn, "slow "+operation, attrs...)
+	default:
+		l.logger.LogAttrs(ctx, slog.LevelDebug, done, attrs...)
+	}
+
+	return err
+}
+
+// messageAttrs returns the attributes identifying msg.
+func messageAttrs[R any](msg api.Message[R]) []slog.Attr {
+
+	if identified, ok := msg.(api.Identified); ok {
+		if id := identified.GetMessageID(); id != "" {
+			return []slog.Attr{slog.String("message_id", id)}
+		}
+	}
+
+	return nil
+}
+
+// errorAttrs returns the attributes describing err.
+func errorAttrs(err error) []slog.Attr {
+	return []slog.Attr{
+		slog.String("error", err.Error()),
+		slog.String("category", api.ErrorCategory(err)),
+	}
+}
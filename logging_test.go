@@ -0,0 +1,113 @@
+package ezQue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pgvanniekerk/ezQue/api"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// identifiedMessage is a fakeMessage with a message ID.
+type identifiedMessage struct {
+	fakeMessage
+}
+
+func (m *identifiedMessage) GetMessageID() string { return "id-" + m.text }
+
+// logRecords decodes the JSON log records written to buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestWithLogger ensures that the lifecycle and the operations on a Queue are logged.
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	fake := &ackQueue{}
+	q := connectIntercepted(t, fake, WithLogger[string](logger, "orders", time.Hour))
+	ctx := context.Background()
+
+	if err := q.Enqueue(ctx, &identifiedMessage{fakeMessage{text: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := q.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Ack(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Dequeue(ctx); err == nil {
+		t.Fatal("expected the empty queue to time out")
+	}
+	if err := q.Disconnect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, record := range logRecords(t, &buf) {
+		got = append(got, record["level"].(string)+" "+record["msg"].(string))
+	}
+	want := []string{
+		"INFO queue connected",
+		"DEBUG message enqueued",
+		"DEBUG message dequeued",
+		"DEBUG message acknowledged",
+		"DEBUG dequeue failed",
+		"INFO queue disconnected",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected log records:\n%s", strings.Join(got, "\n"))
+	}
+	if record := logRecords(t, &buf)[1]; record["message_id"] != "id-a" {
+		t.Fatalf("expected the message ID to be logged, got %v", record)
+	}
+	for _, record := range logRecords(t, &buf) {
+		if record["queue"] != "orders" {
+			t.Fatalf("expected the queue name to be logged, got %v", record)
+		}
+	}
+}
+
+// TestWithLoggerQuiet ensures that only the lifecycle and failures are logged at the
+// default level.
+func TestWithLoggerQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	fake := &ackQueue{}
+	q := connectIntercepted(t, fake,
+		WithLogger[string](logger, "", 0),
+		WithEnqueueInterceptors(func(ctx context.Context, msg api.Message[string], next EnqueueFunc[string]) error {
+			if msg.Text() == "bad" {
+				return api.ErrPayloadTooLarge
+			}
+			return next(ctx, msg)
+		}),
+	)
+	ctx := context.Background()
+
+	_ = q.Enqueue(ctx, &fakeMessage{text: "good"})
+	_ = q.Enqueue(ctx, &fakeMessage{text: "bad"})
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 log records, got %d: %v", len(records), records)
+	}
+	if records[1]["msg"] != "enqueue failed" || records[1]["category"] != "payload_too_large" {
+		t.Fatalf("unexpected log record %v", records[1])
+	}
+}
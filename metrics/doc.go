@@ -0,0 +1,20 @@
+// Package metrics reports metrics of the operations on an ezQue Queue, whichever queue system it
+// connects to.
+//
+// Central to the package is the Interceptors function, whose result is passed to ezQue.Connect
+// with ezQue.WithInterceptors. It reports to a Metrics, a small interface with adapters for the
+// Prometheus client, NewPrometheus, and for the standard library's expvar, NewExpvar:
+//
+//   - enqueues and dequeues, with the size of the payload,
+//   - how long each Dequeue waited for a message,
+//   - how long messages spent in the queue, from Enqueue to Dequeue,
+//   - acknowledgements with Ack and NAck, with how long the message was handled for,
+//   - errors, by operation and category (see api.ErrorCategory).
+//
+// The time in the queue is measured from when the queue system accepted the message, for messages
+// that implement api.Timestamped, such as those of Kafka, JetStream, Redis and SQS. Other messages
+// can carry it in a property added on Enqueue with WithEnqueuedAtProperty. Dequeues that find no
+// message before their context is done are not reported as errors.
+//
+// Note: This package relies on "ezQue", "ezQue/api" and "github.com/prometheus/client_golang".
+package metrics
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"expvar"
+	"time"
+)
+
+// Expvar is a Metrics that publishes counters and totals with the expvar package.
+type Expvar struct {
+	vars *expvar.Map
+}
+
+// NewExpvar returns a Metrics published as the expvar map with the given name, which
+// must not already be published. The map holds, by operation:
+//
+//   - <operation>_messages: the number of messages,
+//   - <operation>_bytes: the total size of their payloads,
+//   - dequeue_wait_ns, in_queue_ns and <operation>_handling_ns: total durations, in
+//     nanoseconds, to be divided by the number of messages for the average,
+//   - in_queue_messages: the number of messages in in_queue_ns,
+//   - <operation>_errors_<category>: the number of errors.
+func NewExpvar(name string) *Expvar {
+	return &Expvar{
+		vars: expvar.NewMap(name),
+	}
+}
+
+func (e *Expvar) Enqueued(size int) {
+	e.vars.Add("enqueue_messages", 1)
+	e.vars.Add("enqueue_bytes", int64(size))
+}
+
+func (e *Expvar) Dequeued(size int, wait time.Duration) {
+	e.vars.Add("dequeue_messages", 1)
+	e.vars.Add("dequeue_bytes", int64(size))
+	e.vars.Add("dequeue_wait_ns", int64(wait))
+}
+
+func (e *Expvar) InQueue(d time.Duration) {
+	e.vars.Add("in_queue_messages", 1)
+	e.vars.Add("in_queue_ns", int64(d))
+}
+
+func (e *Expvar) Acked(handling time.Duration) {
+	e.vars.Add("ack_messages", 1)
+	e.vars.Add("ack_handling_ns", int64(handling))
+}
+
+func (e *Expvar) NAcked(handling time.Duration) {
+	e.vars.Add("nack_messages", 1)
+	e.vars.Add("nack_handling_ns", int64(handling))
+}
+
+func (e *Expvar) Failed(operation Operation, category string) {
+	e.vars.Add(string(operation)+"_errors_"+category, 1)
+}
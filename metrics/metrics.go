@@ -0,0 +1,234 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/pgvanniekerk/ezQue/api"
+	"strconv"
+	"time"
+)
+
+// Operation is a Queue operation that an error is reported for.
+type Operation string
+
+const (
+	OperationEnqueue Operation = "enqueue"
+	OperationDequeue Operation = "dequeue"
+	OperationAck     Operation = "ack"
+	OperationNAck    Operation = "nack"
+)
+
+// EnqueuedAtProperty is the message property holding when the message was enqueued,
+// in nanoseconds since the Unix epoch. It is only added with WithEnqueuedAtProperty.
+const EnqueuedAtProperty = "ezque_enqueued_at"
+
+// Metrics receives the measurements of the operations on a Queue. Implementations
+// must be safe for concurrent use.
+type Metrics interface {
+
+	// Enqueued is called for every message enqueued, with the size of its payload
+	// in bytes.
+	Enqueued(size int)
+
+	// Dequeued is called for every message dequeued, with the size of its payload
+	// in bytes and how long Dequeue waited for it.
+	Dequeued(size int, wait time.Duration)
+
+	// InQueue is called for every message dequeued that records when it was
+	// enqueued, with how long it spent in the queue.
+	InQueue(d time.Duration)
+
+	// Acked is called for every message acknowledged with Ack, with how long it
+	// was handled for since it was dequeued.
+	Acked(handling time.Duration)
+
+	// NAcked is called for every message negatively acknowledged with NAck, with
+	// how long it was handled for since it was dequeued.
+	NAcked(handling time.Duration)
+
+	// Failed is called for every operation that returned an error, with the
+	// category of the error from api.ErrorCategory.
+	Failed(operation Operation, category string)
+}
+
+// Options holds the settings of the interceptors returned by Interceptors.
+type Options struct {
+	enqueuedAtProperty bool
+}
+
+// OptionFunc is a function type to set Options.
+type OptionFunc func(*Options)
+
+// WithEnqueuedAtProperty adds EnqueuedAtProperty to every message enqueued that
+// implements api.Properties, so that the time in the queue is also reported for
+// messages that do not implement api.Timestamped. The property is delivered to
+// consumers along with the other properties, so it is not added by default.
+func WithEnqueuedAtProperty() OptionFunc {
+	return func(opts *Options) {
+		opts.enqueuedAtProperty = true
+	}
+}
+
+// Interceptors returns the interceptors that report the operations on a Queue to m,
+// to be passed to ezQue.Connect with ezQue.WithInterceptors.
+func Interceptors[R any](m Metrics, optFuncs ...OptionFunc) ezQue.Interceptors[R] {
+	return newInterceptors[R](m, time.Now, optFuncs...)
+}
+
+// newInterceptors returns the interceptors that report to m, reading the time with now.
+func newInterceptors[R any](m Metrics, now func() time.Time, optFuncs ...OptionFunc) ezQue.Interceptors[R] {
+
+	var opts Options
+	for _, optFunc := range optFuncs {
+		optFunc(&opts)
+	}
+
+	i := &interceptors[R]{
+		metrics: m,
+		now:     now,
+		opts:    opts,
+	}
+
+	return ezQue.Interceptors[R]{
+		Enqueue: i.enqueue,
+		Dequeue: i.dequeue,
+		Ack:     i.ack,
+	}
+}
+
+// interceptors measures the operations on a Queue.
+type interceptors[R any] struct {
+	metrics Metrics
+	now     func() time.Time
+	opts    Options
+}
+
+// enqueue reports the enqueue, and records when the message was enqueued if
+// WithEnqueuedAtProperty was given.
+func (i *interceptors[R]) enqueue(ctx context.Context, msg api.Message[R], next ezQue.EnqueueFunc[R]) error {
+
+	if properties, ok := msg.(api.Properties); ok && i.opts.enqueuedAtProperty {
+		properties.SetProperty(EnqueuedAtProperty, strconv.FormatInt(i.now().UnixNano(), 10))
+	}
+
+	err := next(ctx, msg)
+	if err != nil {
+		i.metrics.Failed(OperationEnqueue, api.ErrorCategory(err))
+		return err
+	}
+
+	i.metrics.Enqueued(len(msg.Text()))
+	return nil
+}
+
+// dequeue reports the dequeue, how long it waited and how long the message spent in
+// the queue. A Dequeue that found no message before ctx was done is not a failure.
+func (i *interceptors[R]) dequeue(ctx context.Context, next ezQue.DequeueFunc[R]) (api.DequeueMessage[R], error) {
+
+	start := i.now()
+	msg, err := next(ctx)
+	if err != nil {
+		if !noMessage(err) {
+			i.metrics.Failed(OperationDequeue, api.ErrorCategory(err))
+		}
+		return nil, err
+	}
+	dequeued := i.now()
+
+	i.metrics.Dequeued(len(msg.Message().Text()), dequeued.Sub(start))
+	if enqueuedAt, ok := enqueuedAt(msg.Message()); ok {
+		i.metrics.InQueue(dequeued.Sub(enqueuedAt))
+	}
+
+	return &measuredMessage[R]{
+		DequeueMessage: msg,
+		dequeued:       dequeued,
+	}, nil
+}
+
+// ack reports the Ack or NAck, and how long the message was handled for.
+func (i *interceptors[R]) ack(ctx context.Context, msg api.DequeueMessage[R], ack bool, next ezQue.AckFunc[R]) error {
+
+	err := next(ctx, msg)
+
+	operation := OperationNAck
+	if ack {
+		operation = OperationAck
+	}
+	if err != nil {
+		i.metrics.Failed(operation, api.ErrorCategory(err))
+		return err
+	}
+
+	// Messages that were not dequeued through these interceptors are not timed
+	measured := findMeasured(msg)
+	if measured == nil {
+		return nil
+	}
+	handling := i.now().Sub(measured.dequeued)
+	if ack {
+		i.metrics.Acked(handling)
+	} else {
+		i.metrics.NAcked(handling)
+	}
+
+	return nil
+}
+
+// measuredMessage is a dequeued message that records when it was dequeued.
+type measuredMessage[R any] struct {
+	api.DequeueMessage[R]
+	dequeued time.Time
+}
+
+// Unwrap returns the dequeued message.
+func (m *measuredMessage[R]) Unwrap() api.DequeueMessage[R] {
+	return m.DequeueMessage
+}
+
+// findMeasured unwraps msg, which other interceptors may have wrapped, to find the
+// measuredMessage. It returns nil if there is none.
+func findMeasured[R any](msg api.DequeueMessage[R]) *measuredMessage[R] {
+
+	for msg != nil {
+		switch m := msg.(type) {
+		case *measuredMessage[R]:
+			return m
+		case interface{ Unwrap() api.DequeueMessage[R] }:
+			msg = m.Unwrap()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// enqueuedAt returns when msg was enqueued, preferring the time recorded by the
+// queue system over EnqueuedAtProperty. It returns false if neither is known.
+func enqueuedAt[R any](msg api.Message[R]) (time.Time, bool) {
+
+	if timestamped, ok := msg.(api.Timestamped); ok {
+		if enqueuedAt := timestamped.GetEnqueuedAt(); !enqueuedAt.IsZero() {
+			return enqueuedAt, true
+		}
+	}
+
+	if properties, ok := msg.(api.Properties); ok {
+		nanos, err := strconv.ParseInt(properties.GetProperty(EnqueuedAtProperty), 10, 64)
+		if err == nil {
+			return time.Unix(0, nanos), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// noMessage reports whether err means that Dequeue found no message, because ctx
+// was cancelled or its deadline passed first, rather than that it failed.
+func noMessage(err error) bool {
+	return errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, api.ErrTimeout)
+}
@@ -0,0 +1,254 @@
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// message is a fake api.Message with api.Properties.
+type message struct {
+	text       string
+	properties map[string]string
+}
+
+func (m *message) Raw() message        { return *m }
+func (m *message) Text() string        { return m.text }
+func (m *message) SetRaw(raw message)  { *m = raw }
+func (m *message) SetText(text string) { m.text = text }
+func (m *message) GetProperty(key string) string {
+	return m.properties[key]
+}
+func (m *message) SetProperty(key string, value string) {
+	if m.properties == nil {
+		m.properties = make(map[string]string)
+	}
+	m.properties[key] = value
+}
+func (m *message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.properties))
+	for key := range m.properties {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// delivery is a dequeued message. NAck fails.
+type delivery struct {
+	msg message
+}
+
+func (d *delivery) Message() api.Message[message] { return &d.msg }
+func (d *delivery) Ack(context.Context) error     { return nil }
+func (d *delivery) NAck(context.Context) error    { return api.ErrConnectionLost }
+
+// fakeQueue is an in-memory enqueuer and dequeuer of messages. Dequeue fails with
+// dequeueErr once the queue is empty.
+type fakeQueue struct {
+	msgs       []message
+	dequeueErr error
+}
+
+func (q *fakeQueue) NewMessage() api.Message[message] { return &message{} }
+func (q *fakeQueue) Enqueue(_ context.Context, msg api.Message[message]) error {
+	if msg.Text() == "" {
+		return api.ErrPayloadTooLarge
+	}
+	q.msgs = append(q.msgs, msg.Raw())
+	return nil
+}
+func (q *fakeQueue) Dequeue(context.Context) (api.DequeueMessage[message], error) {
+	if len(q.msgs) == 0 {
+		return nil, q.dequeueErr
+	}
+	msg := q.msgs[0]
+	q.msgs = q.msgs[1:]
+	return &delivery{msg: msg}, nil
+}
+func (q *fakeQueue) Disconnect(context.Context) error { return nil }
+
+// recorder is a Metrics that records what it is called with.
+type recorder struct {
+	mu        sync.Mutex
+	calls     []string
+	sizes     []int
+	durations []time.Duration
+}
+
+func (r *recorder) record(call string, size int, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	r.sizes = append(r.sizes, size)
+	r.durations = append(r.durations, d)
+}
+
+func (r *recorder) Enqueued(size int)                     { r.record("enqueued", size, 0) }
+func (r *recorder) Dequeued(size int, wait time.Duration) { r.record("dequeued", size, wait) }
+func (r *recorder) InQueue(d time.Duration)               { r.record("in_queue", 0, d) }
+func (r *recorder) Acked(handling time.Duration)          { r.record("acked", 0, handling) }
+func (r *recorder) NAcked(handling time.Duration)         { r.record("nacked", 0, handling) }
+func (r *recorder) Failed(operation Operation, category string) {
+	r.record("failed "+string(operation)+" "+category, 0, 0)
+}
+
+// connectMeasured connects a Queue over fake reporting to m, with a clock that
+// advances a second every time it is read.
+func connectMeasured(t *testing.T, m Metrics, fake *fakeQueue, optFuncs ...OptionFunc) ezQue.Queue[message] {
+
+	clock := time.Unix(0, 0)
+	now := func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	q, err := ezQue.Connect(func(struct{}) (api.Enqueuer[message], api.Dequeuer[message], error) {
+		return fake, fake, nil
+	}, struct{}{}, ezQue.WithInterceptors(newInterceptors[message](m, now, optFuncs...)))
+	require.NoError(t, err)
+
+	return q
+}
+
+func TestInterceptors(t *testing.T) {
+	r := &recorder{}
+	fake := &fakeQueue{dequeueErr: context.DeadlineExceeded}
+	q := connectMeasured(t, r, fake, WithEnqueuedAtProperty())
+	ctx := context.Background()
+
+	require.NoError(t, q.Enqueue(ctx, &message{text: "hello"}))
+	require.NoError(t, q.Enqueue(ctx, &message{text: "world!"}))
+	require.ErrorIs(t, q.Enqueue(ctx, &message{}), api.ErrPayloadTooLarge)
+
+	first, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, first.Ack(ctx))
+	second, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Error(t, second.NAck(ctx))
+	_, err = q.Dequeue(ctx)
+	require.Error(t, err)
+	fake.dequeueErr = api.ErrConnectionLost
+	_, err = q.Dequeue(ctx)
+	require.Error(t, err)
+
+	// The Dequeue that found no message before its deadline is not a failure
+	require.Equal(t, []string{
+		"enqueued", "enqueued", "failed enqueue payload_too_large",
+		"dequeued", "in_queue", "acked",
+		"dequeued", "in_queue", "failed nack connection_lost",
+		"failed dequeue connection_lost",
+	}, r.calls)
+	require.Equal(t, 5, r.sizes[0])
+	require.Equal(t, 6, r.sizes[1])
+	require.Equal(t, 5, r.sizes[3])
+
+	// The clock advances a second on every read: the first message was enqueued at
+	// 1s and dequeued at 5s, after waiting since 4s, and acknowledged at 6s
+	require.Equal(t, time.Second, r.durations[3])
+	require.Equal(t, 4*time.Second, r.durations[4])
+	require.Equal(t, time.Second, r.durations[5])
+}
+
+// timestampedMessage is a message that records when the queue system accepted it.
+type timestampedMessage struct {
+	message
+	enqueuedAt time.Time
+}
+
+func (m *timestampedMessage) GetEnqueuedAt() time.Time { return m.enqueuedAt }
+
+// timestampedDelivery is a dequeued timestampedMessage.
+type timestampedDelivery struct {
+	delivery
+	enqueuedAt time.Time
+}
+
+func (d *timestampedDelivery) Message() api.Message[message] {
+	return &timestampedMessage{message: d.msg, enqueuedAt: d.enqueuedAt}
+}
+
+// timestampedQueue is a fakeQueue whose messages were accepted at enqueuedAt.
+type timestampedQueue struct {
+	fakeQueue
+	enqueuedAt time.Time
+}
+
+func (q *timestampedQueue) Dequeue(ctx context.Context) (api.DequeueMessage[message], error) {
+	msg, err := q.fakeQueue.Dequeue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &timestampedDelivery{delivery: *msg.(*delivery), enqueuedAt: q.enqueuedAt}, nil
+}
+
+// TestInterceptors_Timestamped ensures that EnqueuedAtProperty is only added when
+// asked for, and that the time in the queue is measured from the time recorded by
+// the queue system instead.
+func TestInterceptors_Timestamped(t *testing.T) {
+	r := &recorder{}
+	fake := &timestampedQueue{enqueuedAt: time.Unix(0, 0)}
+	q, err := ezQue.Connect(func(struct{}) (api.Enqueuer[message], api.Dequeuer[message], error) {
+		return fake, fake, nil
+	}, struct{}{}, ezQue.WithInterceptors(newInterceptors[message](r, func() time.Time {
+		return time.Unix(3, 0)
+	})))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, q.Enqueue(ctx, &message{text: "hello"}))
+	require.Empty(t, fake.msgs[0].properties)
+
+	_, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"enqueued", "dequeued", "in_queue"}, r.calls)
+	require.Equal(t, 3*time.Second, r.durations[2])
+}
+
+func TestPrometheus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	p, err := NewPrometheus(reg, "ezque", "orders")
+	require.NoError(t, err)
+
+	p.Enqueued(10)
+	p.Enqueued(20)
+	p.Dequeued(10, time.Second)
+	p.InQueue(2 * time.Second)
+	p.Acked(time.Millisecond)
+	p.Failed(OperationNAck, "connection_lost")
+
+	require.Equal(t, 2.0, testutil.ToFloat64(p.messages.WithLabelValues("enqueue")))
+	require.Equal(t, 1.0, testutil.ToFloat64(p.messages.WithLabelValues("dequeue")))
+	require.Equal(t, 1.0, testutil.ToFloat64(p.messages.WithLabelValues("ack")))
+	require.Equal(t, 1.0, testutil.ToFloat64(p.errors.WithLabelValues("nack", "connection_lost")))
+	require.Equal(t, 9, testutil.CollectAndCount(reg), "expected a series for every operation and label reported")
+
+	// Registering the collectors again fails
+	_, err = NewPrometheus(reg, "ezque", "orders")
+	require.Error(t, err)
+}
+
+func TestExpvar(t *testing.T) {
+	e := NewExpvar("ezque_test")
+
+	e.Enqueued(10)
+	e.Enqueued(20)
+	e.Dequeued(10, time.Second)
+	e.NAcked(time.Millisecond)
+	e.Failed(OperationEnqueue, "timeout")
+
+	vars := expvar.Get("ezque_test").(*expvar.Map)
+	require.Equal(t, "2", vars.Get("enqueue_messages").String())
+	require.Equal(t, "30", vars.Get("enqueue_bytes").String())
+	require.Equal(t, "1000000000", vars.Get("dequeue_wait_ns").String())
+	require.Equal(t, "1", vars.Get("nack_messages").String())
+	require.Equal(t, "1", vars.Get("enqueue_errors_timeout").String())
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Prometheus is a Metrics that reports to Prometheus collectors.
+type Prometheus struct {
+	messages     *prometheus.CounterVec
+	payloadBytes *prometheus.HistogramVec
+	waitSeconds  prometheus.Histogram
+	inQueue      prometheus.Histogram
+	handling     *prometheus.HistogramVec
+	errors       *prometheus.CounterVec
+}
+
+// NewPrometheus returns a Metrics whose collectors are registered with reg, named
+// with the namespace and labelled with the queue name:
+//
+//   - <namespace>_messages_total{queue, operation}
+//   - <namespace>_payload_bytes{queue, operation}
+//   - <namespace>_dequeue_wait_seconds{queue}
+//   - <namespace>_time_in_queue_seconds{queue}
+//   - <namespace>_handling_seconds{queue, operation}
+//   - <namespace>_errors_total{queue, operation, category}
+//
+// The operation label is one of the Operations.
+func NewPrometheus(reg prometheus.Registerer, namespace string, queue string) (*Prometheus, error) {
+
+	constLabels := prometheus.Labels{"queue": queue}
+	p := &Prometheus{
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "messages_total",
+			Help:        "Messages enqueued, dequeued, acknowledged and negatively acknowledged.",
+			ConstLabels: constLabels,
+		}, []string{"operation"}),
+		payloadBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "payload_bytes",
+			Help:        "Size of the payload of messages enqueued and dequeued.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.ExponentialBuckets(64, 4, 8),
+		}, []string{"operation"}),
+		waitSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "dequeue_wait_seconds",
+			Help:        "Time Dequeue waited for a message.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.ExponentialBuckets(0.001, 4, 10),
+		}),
+		inQueue: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "time_in_queue_seconds",
+			Help:        "Time messages spent in the queue, from enqueue to dequeue.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.ExponentialBuckets(0.001, 4, 10),
+		}),
+		handling: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "handling_seconds",
+			Help:        "Time messages were handled for, from dequeue to acknowledgement.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "errors_total",
+			Help:        "Operations that returned an error, by category.",
+			ConstLabels: constLabels,
+		}, []string{"operation", "category"}),
+	}
+
+	for _, collector := range []prometheus.Collector{p.messages, p.payloadBytes, p.waitSeconds, p.inQueue, p.handling, p.errors} {
+		err := reg.Register(collector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Prometheus) Enqueued(size int) {
+	p.messages.WithLabelValues(string(OperationEnqueue)).Inc()
+	p.payloadBytes.WithLabelValues(string(OperationEnqueue)).Observe(float64(size))
+}
+
+func (p *Prometheus) Dequeued(size int, wait time.Duration) {
+	p.messages.WithLabelValues(string(OperationDequeue)).Inc()
+	p.payloadBytes.WithLabelValues(string(OperationDequeue)).Observe(float64(size))
+	p.waitSeconds.Observe(wait.Seconds())
+}
+
+func (p *Prometheus) InQueue(d time.Duration) {
+	p.inQueue.Observe(d.Seconds())
+}
+
+func (p *Prometheus) Acked(handling time.Duration) {
+	p.messages.WithLabelValues(string(OperationAck)).Inc()
+	p.handling.WithLabelValues(string(OperationAck)).Observe(handling.Seconds())
+}
+
+func (p *Prometheus) NAcked(handling time.Duration) {
+	p.messages.WithLabelValues(string(OperationNAck)).Inc()
+	p.handling.WithLabelValues(string(OperationNAck)).Observe(handling.Seconds())
+}
+
+func (p *Prometheus) Failed(operation Operation, category string) {
+	p.errors.WithLabelValues(string(operation), category).Inc()
+}
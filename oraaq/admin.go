@@ -0,0 +1,53 @@
+package oraaq
+
+import (
+	"database/sql"
+	"github.com/pgvanniekerk/ezQue/internal/oraaq"
+)
+
+// Admin creates, alters and drops queues and queue tables through DBMS_AQADM.
+type Admin = oraaq.Admin
+
+// QueueTableOptions holds the settings of a queue table.
+type QueueTableOptions = oraaq.QueueTableOptions
+
+// QueueOptions holds the settings of a queue.
+type QueueOptions = oraaq.QueueOptions
+
+// EventQueueOptions holds the settings of a sharded Transactional Event Queue.
+type EventQueueOptions = oraaq.EventQueueOptions
+
+// RedriveReport lists the messages moved by Admin.Redrive.
+type RedriveReport = oraaq.RedriveReport
+
+// RedrivenMessage identifies a message moved from the exception queue by Admin.Redrive.
+type RedrivenMessage = oraaq.RedrivenMessage
+
+// Privilege is a queue privilege that can be granted with Admin.Grant.
+type Privilege = oraaq.Privilege
+
+const (
+	PrivilegeEnqueue = oraaq.PrivilegeEnqueue
+	PrivilegeDequeue = oraaq.PrivilegeDequeue
+	PrivilegeAll     = oraaq.PrivilegeAll
+)
+
+// PurgeExpired is an Admin.Purge condition that selects the messages moved to the exception queue.
+const PurgeExpired = oraaq.PurgeExpired
+
+// ConnectAdmin connects to the database described by the UrlOptionFuncs and
+// returns an Admin. The Admin should be closed when no longer needed.
+func ConnectAdmin(urlOpts ...UrlOptionFunc) (*Admin, error) {
+
+	db, err := openDB(urlOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return oraaq.NewAdmin(db), nil
+}
+
+// NewAdmin returns an Admin over an existing database connection.
+func NewAdmin(db *sql.DB) *Admin {
+	return oraaq.NewAdmin(db)
+}
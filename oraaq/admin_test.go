@@ -0,0 +1,13 @@
+package oraaq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestConnectAdmin ensures that ConnectAdmin reports an unreachable database.
+func TestConnectAdmin(t *testing.T) {
+	_, err := ConnectAdmin(LocatedAt("localhost", 1), UsingService("pfft"))
+	require.Error(t, err, "An error was expected because no database is listening")
+}
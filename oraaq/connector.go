@@ -1,11 +1,13 @@
 package oraaq
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/pgvanniekerk/ezQue/api"
 	"github.com/pgvanniekerk/ezQue/internal/oraaq"
 	go_ora "github.com/sijms/go-ora/v2"
+	"log/slog"
 )
 
 // OracleAqJms is provided as a queueConnector to ezQueue.Connect method, to connect to an Oracle Advance Queue.
@@ -45,25 +47,23 @@ func connectEnqueue(options OptionFunc) (api.Enqueuer[oraaq.Message], error) {
 		return nil, fmt.Errorf("oraaq: queueName is empty")
 	}
 
-	// Build db URL
-	urlOpts := &urlOptions{
-		keyVals: make(map[string]string),
-	}
-	for _, opt := range opts.urlOpts {
-		opt(urlOpts)
-	}
-
 	// connect to db
-	db, err := sql.Open("oracle", go_ora.BuildUrl(urlOpts.Server, int(urlOpts.Port), urlOpts.Service, urlOpts.Username, urlOpts.Password, urlOpts.keyVals))
-	if err != nil {
-		return nil, err
-	}
-	err = db.Ping()
+	db, err := openDB(opts.urlOpts)
 	if err != nil {
 		return nil, err
 	}
 
 	enq := oraaq.NewEnqueuer(db, opts.queueName)
+	enq.SetConnectionOptions(connectionOptions(opts.urlOpts))
+
+	if table := buildURLOptions(opts.urlOpts).idempotencyTable; table != "" {
+		err = enq.SetIdempotencyTable(table)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
 	return enq, nil
 }
 
@@ -81,14 +81,49 @@ func connectDequeue(options OptionFunc) (api.Dequeuer[oraaq.Message], error) {
 		return nil, fmt.Errorf("oraaq: queueName is empty")
 	}
 
-	// Build db URL
+	// connect to db
+	db, err := openDB(opts.urlOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.queueNames) > 1 {
+		deq := oraaq.NewMultiDequeuer(db, opts.queueNames, opts.consumerName)
+		deq.SetConnectionOptions(connectionOptions(opts.urlOpts))
+		return deq, nil
+	}
+
+	deq := oraaq.NewSubscriberDequeuer(db, opts.queueName, opts.consumerName)
+	deq.SetConnectionOptions(connectionOptions(opts.urlOpts))
+	return deq, nil
+}
+
+// buildURLOptions applies the UrlOptionFuncs to a new urlOptions.
+func buildURLOptions(opts []UrlOptionFunc) *urlOptions {
+
 	urlOpts := &urlOptions{
 		keyVals: make(map[string]string),
 	}
-	for _, opt := range opts.urlOpts {
+	for _, opt := range opts {
 		opt(urlOpts)
 	}
 
+	return urlOpts
+}
+
+// connectionOptions returns the reconnection policy and callbacks set by the
+// UrlOptionFuncs.
+func connectionOptions(opts []UrlOptionFunc) oraaq.ConnectionOptions {
+	return buildURLOptions(opts).connOpts
+}
+
+// openDB builds the db URL from the UrlOptionFuncs and opens a connection pool,
+// checking that the database can be reached.
+func openDB(opts []UrlOptionFunc) (*sql.DB, error) {
+
+	// Build db URL
+	urlOpts := buildURLOptions(opts)
+
 	// connect to db
 	db, err := sql.Open("oracle", go_ora.BuildUrl(urlOpts.Server, int(urlOpts.Port), urlOpts.Service, urlOpts.Username, urlOpts.Password, urlOpts.keyVals))
 	if err != nil {
@@ -96,18 +131,22 @@ func connectDequeue(options OptionFunc) (api.Dequeuer[oraaq.Message], error) {
 	}
 	err = db.Ping()
 	if err != nil {
+		_ = db.Close()
+		urlOpts.log(slog.LevelError, "failed to connect to the database", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	deq := oraaq.NewDequeuer(db, opts.queueName)
-	return deq, nil
+	urlOpts.log(slog.LevelInfo, "connected to the database")
+	return db, nil
 }
 
-// Options struct holds url options and queue name.
+// Options struct holds url options, queue names and consumer name.
 type Options struct {
-	urlOpts   []UrlOptionFunc
-	queueName string
-	db        *sql.DB
+	urlOpts      []UrlOptionFunc
+	queueName    string
+	queueNames   []string
+	consumerName string
+	db           *sql.DB
 }
 
 // OptionFunc is a function type that returns Options.
@@ -123,6 +162,44 @@ func Queue(queue string, urlOpts ...UrlOptionFunc) OptionFunc {
 	}
 }
 
+// Subscription returns an OptionFunc that holds url options, a queue name and the
+// subscriber to dequeue as. It is used with queues that have multiple consumers,
+// such as sharded Transactional Event Queues created with MultipleConsumers, where
+// every subscriber receives each message.
+func Subscription(queue string, consumer string, urlOpts ...UrlOptionFunc) OptionFunc {
+	return func() Options {
+		return Options{
+			urlOpts:      urlOpts,
+			queueName:    queue,
+			consumerName: consumer,
+		}
+	}
+}
+
+// Queues returns an OptionFunc that holds url options and several queue names. The
+// Dequeuer waits on all the queues at once with DBMS_AQ.LISTEN, using a single
+// session, and dequeues from whichever queue has a message ready. The Enqueuer
+// enqueues to the first queue.
+func Queues(queues []string, urlOpts ...UrlOptionFunc) OptionFunc {
+	return Subscriptions(queues, "", urlOpts...)
+}
+
+// Subscriptions returns an OptionFunc like Queues, for queues with multiple consumers
+// that are dequeued as the given subscriber.
+func Subscriptions(queues []string, consumer string, urlOpts ...UrlOptionFunc) OptionFunc {
+	return func() Options {
+		opts := Options{
+			urlOpts:      urlOpts,
+			queueNames:   queues,
+			consumerName: consumer,
+		}
+		if len(queues) > 0 {
+			opts.queueName = queues[0]
+		}
+		return opts
+	}
+}
+
 // UrlOptionFunc is a function type to set urlOptions.
 type UrlOptionFunc func(*urlOptions)
 
@@ -134,6 +211,10 @@ type urlOptions struct {
 	Port     uint16
 	Service  string
 	keyVals  map[string]string
+	connOpts oraaq.ConnectionOptions
+
+	// idempotencyTable is the table the Enqueuer inserts idempotency keys into.
+	idempotencyTable string
 }
 
 // AuthenticatedWith sets username and password for UrlOptionFunc.
@@ -181,3 +262,60 @@ func WithURLOptions(urlOpts map[string]string) UrlOptionFunc {
 		}
 	}
 }
+
+// ReconnectPolicy controls how enqueues and dequeues that failed because the
+// connection to the database was lost are retried.
+type ReconnectPolicy = oraaq.ReconnectPolicy
+
+// WithReconnect retries enqueues and dequeues that failed because the connection to
+// the database was lost, pinging the database with exponential backoff until it can
+// be reached again. Without it, such failures are returned as api.ErrConnectionLost.
+func WithReconnect(policy ReconnectPolicy) UrlOptionFunc {
+	return func(opts *urlOptions) {
+		opts.connOpts.Reconnect = &policy
+	}
+}
+
+// OnConnect sets a callback that is called when the connection to the database is
+// restored after being lost. The enqueue and dequeue connections call it separately.
+func OnConnect(callback func()) UrlOptionFunc {
+	return func(opts *urlOptions) {
+		opts.connOpts.OnConnect = callback
+	}
+}
+
+// WithLogger logs connecting to the database, and losing and restoring the connection,
+// to logger, with the server, service and queue names as attributes.
+func WithLogger(logger *slog.Logger) UrlOptionFunc {
+	return func(opts *urlOptions) {
+		opts.connOpts.Logger = logger
+	}
+}
+
+// log logs a message about the database to the Logger set with WithLogger, if any.
+func (opts *urlOptions) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if opts.connOpts.Logger == nil {
+		return
+	}
+	attrs = append(attrs, slog.String("server", opts.Server), slog.String("service", opts.Service))
+	opts.connOpts.Logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// OnDisconnect sets a callback that is called with the error when the connection to
+// the database is lost. The enqueue and dequeue connections call it separately.
+func OnDisconnect(callback func(err error)) UrlOptionFunc {
+	return func(opts *urlOptions) {
+		opts.connOpts.OnDisconnect = callback
+	}
+}
+
+// WithIdempotencyTable makes the Enqueuer insert the idempotency key of every message
+// that has one (see ezQue.EnqueueIdempotent) into table, in the same transaction as the
+// enqueue. A message whose key is already in the table is not enqueued again. The table
+// can be created with Admin.EnsureIdempotencyTable. Without it, enqueueing a message
+// with an idempotency key fails with api.ErrNotSupported.
+func WithIdempotencyTable(table string) UrlOptionFunc {
+	return func(opts *urlOptions) {
+		opts.idempotencyTable = table
+	}
+}
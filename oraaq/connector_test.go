@@ -21,6 +21,27 @@ func TestQueue(t *testing.T) {
 	_, _ = ezQue.Connect(OracleAqJms, Queue("testQueue"))
 }
 
+// TestSubscription ensures that Subscription holds the queue and consumer names
+// and can be provided to the ezQue.Connect function.
+func TestSubscription(t *testing.T) {
+	opts := Subscription("testQueue", "testConsumer")()
+
+	require.Equal(t, "testQueue", opts.queueName, "The queueName in Options did not match the expected value")
+	require.Equal(t, "testConsumer", opts.consumerName, "The consumerName in Options did not match the expected value")
+	_, _ = ezQue.Connect(OracleAqJms, Subscription("testQueue", "testConsumer"))
+}
+
+// TestQueues ensures that Queues holds the queue names, enqueueing
+// to the first, and can be provided to the ezQue.Connect function.
+func TestQueues(t *testing.T) {
+	opts := Queues([]string{"firstQueue", "secondQueue"})()
+
+	require.Equal(t, "firstQueue", opts.queueName, "The queueName in Options should be the first queue")
+	require.Equal(t, []string{"firstQueue", "secondQueue"}, opts.queueNames, "The queueNames in Options did not match the expected value")
+	require.Empty(t, opts.consumerName)
+	_, _ = ezQue.Connect(OracleAqJms, Subscriptions([]string{"firstQueue", "secondQueue"}, "testConsumer"))
+}
+
 // TestAuthenticatedWith ensures that it correctly sets the config values
 // and is a valid functional option to provide to the Queue method.
 func TestAuthenticatedWith(t *testing.T) {
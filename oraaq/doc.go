@@ -9,6 +9,32 @@
 // function that returns configured Options. Also provided are several helper function types such as
 // UrlOptionFunc to set specific options.
 //
+// The Dequeuer also supports browsing: ezQue.Browse and ezQue.Peek read messages in DBMS_AQ.BROWSE
+// mode, walking the queue with FIRST_MESSAGE and NEXT_MESSAGE navigation, so that operators can
+// inspect its contents without consuming them.
+//
+// Messages carry an optional correlation identifier and reply-to queue name, implementing api.Correlated
+// for ezQue.Requester and ezQue.Responder, and ezQue.DequeueWhere dequeues a specific message
+// by message ID, correlation or dequeue condition, a SQL predicate on the message properties and payload.
+//
+// ezQue.Stats reports the number of ready, waiting and expired messages in the queue, and when the oldest
+// ready message was enqueued, read from the AQ$ view of the queue table.
+//
+// Queues and queue tables are provisioned with Admin, returned by ConnectAdmin, which wraps DBMS_AQADM.
+// Its Ensure variants, such as EnsureQueue, are idempotent so that services can create their queues at
+// startup and tests can set up fixtures in Go. Admin.Redrive moves messages from the exception queue back
+// to their original queue, with a dry-run mode that only reports what would be moved, and Admin.Purge
+// removes all, expired or matching messages with DBMS_AQADM.PURGE_QUEUE_TABLE.
+//
+// Sharded Transactional Event Queues are created with Admin.EnsureEventQueue and used through the same
+// ezQue.Queue API. Queues created with multiple consumers are connected to with Subscription, naming the
+// subscriber to dequeue as, and every subscriber added with Admin.EnsureSubscriber receives each message.
+// With key-based ordering, messages with the same correlation identifier are dequeued in order.
+//
+// Each waiting Dequeue holds a database session. To consume many queues without a session per queue,
+// connect with Queues or Subscriptions: the Dequeuer then waits on all the queues at once with
+// DBMS_AQ.LISTEN, and only dequeues from a queue once it has a message ready.
+//
 // The package also provides low-level functions, connectEnqueue and connectDequeue, to initiate
 // enqueue and dequeue connections individually.
 //
@@ -0,0 +1,12 @@
+package oraaq
+
+import "github.com/pgvanniekerk/ezQue/internal/oraaq"
+
+// Retryable reports whether err is a transient OracleAQ failure that may succeed if
+// the operation is retried. It recognises more Oracle errors than api.Retryable, such
+// as deadlocks and busy resources, and can be used as the ezQue.RetryPolicy Retryable:
+//
+//	q = ezQue.WithRetry(q, ezQue.RetryPolicy{Retryable: oraaq.Retryable})
+func Retryable(err error) bool {
+	return oraaq.Retryable(err)
+}
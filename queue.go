@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/pgvanniekerk/ezQue/api"
 	"golang.org/x/sync/errgroup"
+	"log/slog"
 )
 
 // Queue represents a generic queue interface where M represents any type.
@@ -33,6 +34,11 @@ type Queue[R any] interface {
 	// to ensure the queue properly manages the message lifecycle.
 	Dequeue(ctx context.Context) (api.DequeueMessage[R], error)
 
+	// Health checks that the queue system can be reached, for use in readiness
+	// probes. It returns nil if it can, or if the queue system cannot check, and
+	// otherwise an error such as api.ErrConnectionLost.
+	Health(ctx context.Context) error
+
 	// Disconnect closes the connection with the queue based on the provided context.
 	// It should be called when the queue operations are no longer required.
 	// It returns an error if there was an issue during the disconnection process.
@@ -46,6 +52,15 @@ type Queue[R any] interface {
 type queue[R any] struct {
 	enqueuer api.Enqueuer[R]
 	dequeuer api.Dequeuer[R]
+
+	// Interceptors added by ConnectOptions, called around the enqueuer and
+	// dequeuer.
+	enqueueInterceptors []EnqueueInterceptor[R]
+	dequeueInterceptors []DequeueInterceptor[R]
+	ackInterceptors     []AckInterceptor[R]
+
+	// logger logs the connection lifecycle, if set with WithLogger.
+	logger *slog.Logger
 }
 
 // NewMessage returns a new message object of type api.Message[R], created by the queue's enqueuer.
@@ -58,15 +73,35 @@ func (q *queue[R]) NewMessage() api.Message[R] {
 }
 
 // Enqueue adds an item of type M to the queue, following the context.
-// It delegates the operation to its enqueuer and returns any error produced during this operation.
+// It delegates the operation to its enqueuer, through the enqueue interceptors, and returns any error
+// produced during this operation.
 func (q *queue[R]) Enqueue(ctx context.Context, msg api.Message[R]) error {
-	return q.enqueuer.Enqueue(ctx, msg)
+	return q.interceptEnqueue(ctx, msg, q.enqueuer.Enqueue)
 }
 
 // Dequeue retrieves and removes an item from the queue, following the context.
-// It delegates the operation to its dequeuer and returns the dequeued item along with any error that occurred during the operation.
+// It delegates the operation to its dequeuer, through the dequeue interceptors, and returns the dequeued item
+// along with any error that occurred during the operation.
 func (q *queue[R]) Dequeue(ctx context.Context) (api.DequeueMessage[R], error) {
-	return q.dequeuer.Dequeue(ctx)
+	return q.interceptDequeue(ctx, q.dequeuer.Dequeue)
+}
+
+// Health checks both the enqueuer and the dequeuer, if they support api.HealthChecker,
+// and returns the first error.
+func (q *queue[R]) Health(ctx context.Context) error {
+
+	if checker, ok := q.enqueuer.(api.HealthChecker); ok {
+		if err := checker.Health(ctx); err != nil {
+			return err
+		}
+	}
+	if checker, ok := q.dequeuer.(api.HealthChecker); ok {
+		if err := checker.Health(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Disconnect disconnects from the queue by calling the `Disconnect()` method on both the enqueuer and the dequeuer.
@@ -83,5 +118,14 @@ func (q *queue[R]) Disconnect(ctx context.Context) error {
 		return q.dequeuer.Disconnect(ctx)
 	})
 
-	return errGrp.Wait()
+	err := errGrp.Wait()
+	if q.logger != nil {
+		if err != nil {
+			q.logger.LogAttrs(ctx, slog.LevelError, "queue disconnect failed", errorAttrs(err)...)
+		} else {
+			q.logger.LogAttrs(ctx, slog.LevelInfo, "queue disconnected")
+		}
+	}
+
+	return err
 }
@@ -0,0 +1,119 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"golang.org/x/time/rate"
+	"sync"
+)
+
+// RateLimit is a token bucket limiting how often an operation runs: Rate times per
+// second on average, in bursts of up to Burst. Burst defaults to 1. A Rate of zero or
+// less does not limit the operation.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// limiter returns the rate.Limiter of the RateLimit, which does not limit anything if
+// Rate is zero or less.
+func (l RateLimit) limiter() *rate.Limiter {
+
+	if l.Rate <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+
+	burst := l.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return rate.NewLimiter(rate.Limit(l.Rate), burst)
+}
+
+// WithEnqueueRateLimit limits how often the Queue enqueues. Enqueue waits for the rate
+// limit, or returns the context's error if it is done first.
+func WithEnqueueRateLimit[R any](limit RateLimit) ConnectOption[R] {
+
+	limiter := limit.limiter()
+
+	return WithEnqueueInterceptors(func(ctx context.Context, msg api.Message[R], next EnqueueFunc[R]) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		return next(ctx, msg)
+	})
+}
+
+// WithDequeueRateLimit limits how often the Queue dequeues. Dequeue waits for the rate
+// limit, or returns the context's error if it is done first.
+func WithDequeueRateLimit[R any](limit RateLimit) ConnectOption[R] {
+
+	limiter := limit.limiter()
+
+	return WithDequeueInterceptors(func(ctx context.Context, next DequeueFunc[R]) (api.DequeueMessage[R], error) {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return next(ctx)
+	})
+}
+
+// WithMaxInFlight limits the number of messages dequeued from the Queue that have not
+// been acknowledged with Ack or NAck yet. Once max messages are in flight, Dequeue
+// waits for one of them to be acknowledged, or returns the context's error if it is
+// done first. A max of zero or less does not limit the messages in flight.
+func WithMaxInFlight[R any](max int) ConnectOption[R] {
+
+	if max <= 0 {
+		return func(*queue[R]) {}
+	}
+
+	slots := make(chan struct{}, max)
+
+	return WithDequeueInterceptors(func(ctx context.Context, next DequeueFunc[R]) (api.DequeueMessage[R], error) {
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		msg, err := next(ctx)
+		if err != nil {
+			<-slots
+			return nil, err
+		}
+
+		return &inFlightMessage[R]{
+			DequeueMessage: msg,
+			release:        func() { <-slots },
+		}, nil
+	})
+}
+
+// inFlightMessage is a dequeued message that releases its in-flight slot when it is
+// acknowledged.
+type inFlightMessage[R any] struct {
+	api.DequeueMessage[R]
+	release func()
+	once    sync.Once
+}
+
+// Unwrap returns the dequeued message.
+func (m *inFlightMessage[R]) Unwrap() api.DequeueMessage[R] {
+	return m.DequeueMessage
+}
+
+// Ack acknowledges the message and releases its slot, even if Ack fails, since the
+// message is no longer held.
+func (m *inFlightMessage[R]) Ack(ctx context.Context) error {
+	defer m.once.Do(m.release)
+	return m.DequeueMessage.Ack(ctx)
+}
+
+// NAck negatively acknowledges the message and releases its slot.
+func (m *inFlightMessage[R]) NAck(ctx context.Context) error {
+	defer m.once.Do(m.release)
+	return m.DequeueMessage.NAck(ctx)
+}
@@ -0,0 +1,117 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestEnqueueRateLimit ensures that enqueues beyond the burst wait for the rate limit.
+func TestEnqueueRateLimit(t *testing.T) {
+	fake := &ackQueue{}
+	q := connectIntercepted(t, fake, WithEnqueueRateLimit[string](RateLimit{Rate: 1, Burst: 2}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(ctx, &fakeMessage{text: "a"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The third enqueue would have to wait a second
+	if err := q.Enqueue(ctx, &fakeMessage{text: "a"}); err == nil {
+		t.Fatal("expected the third enqueue to be rate limited")
+	}
+	if len(fake.msgs) != 2 {
+		t.Fatalf("expected 2 messages enqueued, got %d", len(fake.msgs))
+	}
+}
+
+// TestDequeueRateLimit ensures that dequeues beyond the burst wait for the rate limit.
+func TestDequeueRateLimit(t *testing.T) {
+	fake := &ackQueue{fakeQueue: fakeQueue{msgs: []string{"a", "b"}}}
+	q := connectIntercepted(t, fake, WithDequeueRateLimit[string](RateLimit{Rate: 1}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := q.Dequeue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Dequeue(ctx); err == nil {
+		t.Fatal("expected the second dequeue to be rate limited")
+	}
+}
+
+// TestMaxInFlight ensures that Dequeue waits while the maximum number of messages is
+// unacknowledged.
+func TestMaxInFlight(t *testing.T) {
+	fake := &ackQueue{fakeQueue: fakeQueue{msgs: []string{"a", "b", "c"}}}
+	q := connectIntercepted(t, fake, WithMaxInFlight[string](2))
+	ctx := context.Background()
+
+	first, err := q.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := q.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A third message cannot be dequeued until one is acknowledged
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.Dequeue(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the dequeue to wait, got %v", err)
+	}
+
+	// Acknowledging a message twice releases its slot once
+	if err := first.Ack(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_ = first.Ack(ctx)
+	third, err := q.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Message().Text() != "c" {
+		t.Fatalf("expected message c, got %s", third.Message().Text())
+	}
+
+	waitCtx, cancel = context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.Dequeue(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the dequeue to wait, got %v", err)
+	}
+	_ = second.NAck(ctx)
+}
+
+// TestNoLimits ensures that limits of zero or less do not limit anything, instead of
+// blocking or panicking.
+func TestNoLimits(t *testing.T) {
+	fake := &ackQueue{fakeQueue: fakeQueue{msgs: []string{"a", "b", "c"}}}
+	q := connectIntercepted(t, fake,
+		WithMaxInFlight[string](0),
+		WithMaxInFlight[string](-1),
+		WithEnqueueRateLimit[string](RateLimit{}),
+		WithDequeueRateLimit[string](RateLimit{Rate: -1}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if _, err := q.Dequeue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue(ctx, &fakeMessage{text: "a"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
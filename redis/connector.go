@@ -0,0 +1,223 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultBlock is used when no WithBlock option is given.
+	defaultBlock = 5 * time.Second
+
+	// defaultReclaimAfter is used when no WithReclaimAfter option is given.
+	defaultReclaimAfter = time.Minute
+)
+
+// RedisStreams is provided as a queueConnector to ezQueue.Connect method, to connect to a Redis stream.
+func RedisStreams(options OptionFunc) (api.Enqueuer[redis.Message], api.Dequeuer[redis.Message], error) {
+	return connect(options)
+}
+
+// connect establishes both enqueue and dequeue connections.
+func connect(opts OptionFunc) (api.Enqueuer[redis.Message], api.Dequeuer[redis.Message], error) {
+
+	// Initialise Enqueuer
+	enq, err := connectEnqueue(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialise Dequeuer
+	deq, err := connectDequeue(opts)
+	if err != nil {
+		_ = enq.Disconnect(context.Background())
+		return nil, nil, err
+	}
+
+	return enq, deq, nil
+}
+
+// connectEnqueue establishes an enqueue connection.
+func connectEnqueue(options OptionFunc) (api.Enqueuer[redis.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("redis: options is nil")
+	}
+	opts := options()
+
+	// Validate stream
+	if opts.stream == "" {
+		return nil, fmt.Errorf("redis: stream is empty")
+	}
+
+	connOpts := buildConnOptions(opts)
+
+	// connect to redis
+	client := goredis.NewClient(connOpts.redisOpts)
+	err := client.Ping(context.Background()).Err()
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	enq := redis.NewEnqueuer(client, opts.stream)
+	return enq, nil
+}
+
+// connectDequeue establishes a dequeue connection, creating the stream and
+// consumer group if they do not exist yet.
+func connectDequeue(options OptionFunc) (api.Dequeuer[redis.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("redis: options is nil")
+	}
+	opts := options()
+
+	// Validate stream and group
+	if opts.stream == "" {
+		return nil, fmt.Errorf("redis: stream is empty")
+	}
+	if opts.group == "" {
+		return nil, fmt.Errorf("redis: consumer group is empty")
+	}
+
+	connOpts := buildConnOptions(opts)
+
+	// connect to redis
+	client := goredis.NewClient(connOpts.redisOpts)
+	err := client.Ping(context.Background()).Err()
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	// create the consumer group, reading the stream from its start
+	err = client.XGroupCreateMkStream(context.Background(), opts.stream, opts.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		_ = client.Close()
+		return nil, err
+	}
+
+	deq := redis.NewDequeuer(client, opts.stream, opts.group, connOpts.consumer, connOpts.block, connOpts.reclaimAfter)
+	return deq, nil
+}
+
+// buildConnOptions applies the ConnOptionFuncs over the defaults.
+func buildConnOptions(opts Options) *connOptions {
+	connOpts := &connOptions{
+		redisOpts:    &goredis.Options{Addr: "localhost:6379"},
+		consumer:     defaultConsumer(),
+		block:        defaultBlock,
+		reclaimAfter: defaultReclaimAfter,
+	}
+	for _, opt := range opts.connOpts {
+		opt(connOpts)
+	}
+	return connOpts
+}
+
+// defaultConsumer names the consumer after the host and process, so that
+// every process gets its own entry in the consumer group.
+func defaultConsumer() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "ezque"
+	}
+	return host + "-" + strconv.Itoa(os.Getpid())
+}
+
+// Options struct holds connection options, the stream and the consumer group.
+type Options struct {
+	connOpts []ConnOptionFunc
+	stream   string
+	group    string
+}
+
+// OptionFunc is a function type that returns Options.
+type OptionFunc func() Options
+
+// Stream returns an OptionFunc that holds connection options, a stream and the
+// consumer group that Dequeue reads the stream in.
+func Stream(stream string, group string, connOpts ...ConnOptionFunc) OptionFunc {
+	return func() Options {
+		return Options{
+			connOpts: connOpts,
+			stream:   stream,
+			group:    group,
+		}
+	}
+}
+
+// ConnOptionFunc is a function type to set connOptions.
+type ConnOptionFunc func(*connOptions)
+
+// connOptions struct holds the information required for creating connections.
+type connOptions struct {
+	redisOpts    *goredis.Options
+	consumer     string
+	block        time.Duration
+	reclaimAfter time.Duration
+}
+
+// LocatedAt sets the server address, given as host:port, for ConnOptionFunc.
+func LocatedAt(addr string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.redisOpts.Addr = addr
+	}
+}
+
+// AuthenticatedWith sets username and password for ConnOptionFunc.
+func AuthenticatedWith(username string, password string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.redisOpts.Username = username
+		opts.redisOpts.Password = password
+	}
+}
+
+// UsingDB selects the Redis database for ConnOptionFunc.
+func UsingDB(db int) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.redisOpts.DB = db
+	}
+}
+
+// WithRedisOptions allows any other go-redis client option to be set.
+func WithRedisOptions(fn func(*goredis.Options)) ConnOptionFunc {
+	return func(opts *connOptions) {
+		fn(opts.redisOpts)
+	}
+}
+
+// AsConsumer sets the name the Dequeuer reads the stream as within its consumer group.
+// It defaults to the host name and process id.
+func AsConsumer(consumer string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.consumer = consumer
+	}
+}
+
+// WithBlock sets how long a single XREADGROUP waits for new entries before
+// pending entries are checked for reclaiming again.
+func WithBlock(block time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.block = block
+	}
+}
+
+// WithReclaimAfter sets how long an entry may stay pending without being
+// acknowledged before another consumer reclaims it. It should exceed the time
+// needed to process a message.
+func WithReclaimAfter(reclaimAfter time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.reclaimAfter = reclaimAfter
+	}
+}
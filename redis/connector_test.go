@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pgvanniekerk/ezQue"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+// TestConnector ensures that RedisStreams can be provided
+// to the ezQue.Connect function.
+func TestConnector(t *testing.T) {
+	_, _ = ezQue.Connect(RedisStreams, nil)
+}
+
+// TestConnOptions ensures that the ConnOptionFuncs correctly set the config values
+func TestConnOptions(t *testing.T) {
+	opts := buildConnOptions(Stream("stream", "group",
+		LocatedAt("redis:6380"),
+		AuthenticatedWith("user", "pass"),
+		UsingDB(2),
+		WithRedisOptions(func(o *goredis.Options) { o.PoolSize = 3 }),
+		AsConsumer("consumer"),
+		WithBlock(time.Second),
+		WithReclaimAfter(time.Hour),
+	)())
+
+	require.Equal(t, "redis:6380", opts.redisOpts.Addr)
+	require.Equal(t, "user", opts.redisOpts.Username)
+	require.Equal(t, "pass", opts.redisOpts.Password)
+	require.Equal(t, 2, opts.redisOpts.DB)
+	require.Equal(t, 3, opts.redisOpts.PoolSize)
+	require.Equal(t, "consumer", opts.consumer)
+	require.Equal(t, time.Second, opts.block)
+	require.Equal(t, time.Hour, opts.reclaimAfter)
+}
+
+//
+// connect
+//
+
+type ConnectTestSuite struct {
+	suite.Suite
+	srv *miniredis.Miniredis
+}
+
+func TestConnectTestSuite(t *testing.T) {
+	suite.Run(t, new(ConnectTestSuite))
+}
+
+func (suite *ConnectTestSuite) SetupTest() {
+	suite.srv = miniredis.RunT(suite.T())
+}
+
+func (suite *ConnectTestSuite) Test_FailOnNilOptions() {
+	_, _, err := connect(nil)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyStream() {
+	_, _, err := connect(Stream("", "group", LocatedAt(suite.srv.Addr())))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyGroup() {
+	_, _, err := connect(Stream("stream", "", LocatedAt(suite.srv.Addr())))
+	assert.NotNil(suite.T(), err)
+}
+
+// Test_ExistingGroup tests that connecting to a group that already exists succeeds.
+func (suite *ConnectTestSuite) Test_ExistingGroup() {
+	for i := 0; i < 2; i++ {
+		enq, deq, err := connect(Stream("stream", "group", LocatedAt(suite.srv.Addr())))
+		suite.Require().NoError(err)
+		suite.NoError(enq.Disconnect(context.Background()))
+		suite.NoError(deq.Disconnect(context.Background()))
+	}
+}
+
+// Test_EnqueueDequeue tests a round trip through a Queue connected with RedisStreams.
+func (suite *ConnectTestSuite) Test_EnqueueDequeue() {
+	q, err := ezQue.Connect(RedisStreams, Stream("orders", "processor",
+		LocatedAt(suite.srv.Addr()),
+		WithBlock(50*time.Millisecond),
+	))
+	suite.Require().NoError(err)
+	defer func() { suite.NoError(q.Disconnect(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg := q.NewMessage()
+	msg.SetText("test message")
+	suite.Require().NoError(q.Enqueue(ctx, msg))
+
+	deqMsg, err := q.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.NoError(deqMsg.Ack(ctx))
+}
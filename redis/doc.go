@@ -0,0 +1,18 @@
+// Package redis provides Go functions and types for working with Redis Streams through the ezQue
+// Queue abstraction.
+//
+// Central to the package is the RedisStreams function. This function takes in an OptionFunc parameter
+// for customizing the connection options and returns Enqueuer and Dequeuer instances associated
+// with the specified stream.
+//
+// Enqueue appends an entry to the stream with XADD. Dequeue reads the stream with XREADGROUP as a
+// consumer in the consumer group given to Stream, which is created on connect if it does not exist.
+// Ack acknowledges the entry with XACK.
+//
+// Entries that are read but never acknowledged stay pending in the consumer group. Before reading
+// new entries, Dequeue reclaims with XCLAIM any entry that has been pending for longer than
+// WithReclaimAfter, so the entries of a consumer that crashed are picked up by the others. NAck
+// marks the entry as idle for that long, so it is reclaimed by the next Dequeue.
+//
+// Note: This package relies on "ezQue/api", "ezQue/internal/redis" and "github.com/redis/go-redis/v9".
+package redis
@@ -0,0 +1,170 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"math/rand/v2"
+	"time"
+)
+
+// Defaults of the RetryPolicy.
+const (
+	defaultRetryAttempts       = 3
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy controls how failed enqueues are retried. The wait between attempts
+// starts at InitialBackoff and doubles after each attempt, up to MaxBackoff.
+type RetryPolicy struct {
+
+	// MaxAttempts is the number of attempts, including the first, before the
+	// error is returned. It defaults to 3.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the second attempt. It defaults to 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts. It defaults to 5s.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction, between 0 and 1, by which each wait is randomly
+	// shortened, so that producers failing together do not retry together.
+	// Zero waits the full backoff.
+	Jitter float64
+
+	// Retryable reports whether an error is worth retrying. It defaults to
+	// api.Retryable, which queue systems such as OracleAQ map their transient
+	// errors onto.
+	Retryable func(err error) bool
+}
+
+// WithRetry returns a Queue that retries failed enqueues according to policy. Dequeues
+// are passed through unchanged.
+func WithRetry[R any](q Queue[R], policy RetryPolicy) Queue[R] {
+
+	if inner, ok := q.(*queue[R]); ok {
+		retried := *inner
+		retried.enqueuer = RetryEnqueuer(inner.enqueuer, policy)
+		return &retried
+	}
+
+	return &retryQueue[R]{
+		Queue:    q,
+		enqueuer: RetryEnqueuer[R](q, policy),
+	}
+}
+
+// RetryEnqueuer returns an api.Enqueuer that retries failed enqueues of enq according
+// to policy. Batches are retried whole, so messages of a batch that partly failed may
+// be enqueued twice.
+func RetryEnqueuer[R any](enq api.Enqueuer[R], policy RetryPolicy) api.Enqueuer[R] {
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultRetryAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultRetryMaxBackoff
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = api.Retryable
+	}
+
+	return &retryEnqueuer[R]{
+		enqueuer: enq,
+		policy:   policy,
+	}
+}
+
+// retryEnqueuer is an api.Enqueuer that retries the enqueues of its enqueuer.
+type retryEnqueuer[R any] struct {
+	enqueuer api.Enqueuer[R]
+	policy   RetryPolicy
+}
+
+func (e *retryEnqueuer[R]) NewMessage() api.Message[R] {
+	return e.enqueuer.NewMessage()
+}
+
+// Enqueue enqueues msg, retrying as allowed by the RetryPolicy.
+func (e *retryEnqueuer[R]) Enqueue(ctx context.Context, msg api.Message[R]) error {
+	return e.retry(ctx, func() error {
+		return e.enqueuer.Enqueue(ctx, msg)
+	})
+}
+
+// EnqueueBatch enqueues msgs in a batch if the enqueuer supports api.BatchEnqueuer,
+// retrying the whole batch, and otherwise enqueues and retries them one at a time.
+func (e *retryEnqueuer[R]) EnqueueBatch(ctx context.Context, msgs []api.Message[R]) error {
+
+	if batcher, ok := e.enqueuer.(api.BatchEnqueuer[R]); ok {
+		return e.retry(ctx, func() error {
+			return batcher.EnqueueBatch(ctx, msgs)
+		})
+	}
+
+	for _, msg := range msgs {
+		err := e.Enqueue(ctx, msg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Health delegates to the enqueuer if it supports api.HealthChecker.
+func (e *retryEnqueuer[R]) Health(ctx context.Context) error {
+
+	if checker, ok := e.enqueuer.(api.HealthChecker); ok {
+		return checker.Health(ctx)
+	}
+
+	return nil
+}
+
+func (e *retryEnqueuer[R]) Disconnect(ctx context.Context) error {
+	return e.enqueuer.Disconnect(ctx)
+}
+
+// retry runs op until it succeeds, fails with an error that is not retryable, runs
+// out of attempts or the context is done.
+func (e *retryEnqueuer[R]) retry(ctx context.Context, op func() error) error {
+
+	backoff := e.policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+
+		err := op()
+		if err == nil || attempt >= e.policy.MaxAttempts || !e.policy.Retryable(err) {
+			return err
+		}
+
+		wait := backoff
+		if e.policy.Jitter > 0 {
+			wait -= time.Duration(rand.Float64() * e.policy.Jitter * float64(wait))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, e.policy.MaxBackoff)
+	}
+}
+
+// retryQueue retries the enqueues of a Queue that was not returned by Connect.
+type retryQueue[R any] struct {
+	Queue[R]
+	enqueuer api.Enqueuer[R]
+}
+
+func (q *retryQueue[R]) Enqueue(ctx context.Context, msg api.Message[R]) error {
+	return q.enqueuer.Enqueue(ctx, msg)
+}
@@ -0,0 +1,88 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	"testing"
+	"time"
+)
+
+// flakyQueue is a fakeQueue whose first enqueues fail with err.
+type flakyQueue struct {
+	fakeQueue
+	failures int
+	attempts int
+	err      error
+}
+
+func (q *flakyQueue) Enqueue(ctx context.Context, msg api.Message[string]) error {
+	q.attempts++
+	if q.attempts <= q.failures {
+		return q.err
+	}
+	return q.fakeQueue.Enqueue(ctx, msg)
+}
+
+// TestWithRetry ensures that transient enqueue failures are retried.
+func TestWithRetry(t *testing.T) {
+	fake := &flakyQueue{failures: 2, err: api.ErrConnectionLost}
+	q := WithRetry(connectFake[string](t, fake), RetryPolicy{InitialBackoff: time.Millisecond, Jitter: 0.5})
+
+	err := q.Enqueue(context.Background(), &fakeMessage{text: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", fake.attempts)
+	}
+	if len(fake.msgs) != 1 {
+		t.Fatalf("expected 1 message enqueued, got %d", len(fake.msgs))
+	}
+}
+
+// TestWithRetryMaxAttempts ensures that the error is returned once the attempts run out.
+func TestWithRetryMaxAttempts(t *testing.T) {
+	fake := &flakyQueue{failures: 5, err: api.ErrConnectionLost}
+	q := WithRetry(connectFake[string](t, fake), RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	err := q.Enqueue(context.Background(), &fakeMessage{text: "a"})
+	if !errors.Is(err, api.ErrConnectionLost) {
+		t.Fatalf("expected api.ErrConnectionLost, got %v", err)
+	}
+	if fake.attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", fake.attempts)
+	}
+}
+
+// TestWithRetryNotRetryable ensures that errors the classifier rejects are not retried.
+func TestWithRetryNotRetryable(t *testing.T) {
+	fake := &flakyQueue{failures: 1, err: api.ErrUnauthorized}
+	q := WithRetry(connectFake[string](t, fake), RetryPolicy{InitialBackoff: time.Millisecond})
+
+	err := q.Enqueue(context.Background(), &fakeMessage{text: "a"})
+	if !errors.Is(err, api.ErrUnauthorized) {
+		t.Fatalf("expected api.ErrUnauthorized, got %v", err)
+	}
+	if fake.attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", fake.attempts)
+	}
+}
+
+// TestWithRetryClassifier ensures that a custom classifier decides what is retried.
+func TestWithRetryClassifier(t *testing.T) {
+	errBusy := errors.New("busy")
+	fake := &flakyQueue{failures: 1, err: errBusy}
+	q := WithRetry(connectFake[string](t, fake), RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		Retryable:      func(err error) bool { return errors.Is(err, errBusy) },
+	})
+
+	err := q.Enqueue(context.Background(), &fakeMessage{text: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", fake.attempts)
+	}
+}
@@ -0,0 +1,230 @@
+package ezQue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/pgvanniekerk/ezQue/api"
+	"time"
+)
+
+// Defaults of the Responder's backoff after a failed request.
+const (
+	defaultFailureBackoff    = 100 * time.Millisecond
+	defaultMaxFailureBackoff = 30 * time.Second
+)
+
+// Requester sends requests to a queue and waits for their replies on a reply queue.
+// Messages must implement api.Correlated, so that the request can carry a correlation
+// ID and the reply queue name, and the reply can be matched to the request.
+type Requester[R any] struct {
+	requests Queue[R]
+	replies  Queue[R]
+	replyTo  string
+}
+
+// NewRequester returns a Requester that enqueues requests on requests and waits for
+// replies on replies. replyTo is the name responders should enqueue replies to, which
+// is the name replies was connected with.
+func NewRequester[R any](requests Queue[R], replies Queue[R], replyTo string) *Requester[R] {
+	return &Requester[R]{
+		requests: requests,
+		replies:  replies,
+		replyTo:  replyTo,
+	}
+}
+
+// Request enqueues msg with a newly generated correlation ID and the reply-to queue,
+// and waits for the reply with the same correlation ID until the context is cancelled.
+// The reply is dequeued by its correlation ID, so the reply queue must support
+// api.SelectiveDequeuer; otherwise api.ErrNotSupported is returned before msg is
+// enqueued, since scanning a shared reply queue would hand other requests' replies
+// back to it over and over. The reply must be acknowledged by the caller.
+func (r *Requester[R]) Request(ctx context.Context, msg api.Message[R]) (api.DequeueMessage[R], error) {
+
+	if !selective(r.replies) {
+		return nil, fmt.Errorf("ezQue: reply queue does not support selective dequeue: %w", api.ErrNotSupported)
+	}
+
+	correlated, ok := msg.(api.Correlated)
+	if !ok {
+		return nil, fmt.Errorf("ezQue: message does not support correlation: %w", api.ErrNotSupported)
+	}
+
+	correlationID, err := newCorrelationID()
+	if err != nil {
+		return nil, err
+	}
+	correlated.SetCorrelationID(correlationID)
+	correlated.SetReplyTo(r.replyTo)
+
+	err = r.requests.Enqueue(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return DequeueWhere(ctx, r.replies, api.Selector{CorrelationID: correlationID})
+}
+
+// newCorrelationID returns a random 128-bit correlation ID in hex.
+func newCorrelationID() (string, error) {
+	var id [16]byte
+	_, err := rand.Read(id[:])
+	if err != nil {
+		return "", fmt.Errorf("ezQue: failed to generate correlation id: %w", err)
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// Handler processes a request and fills in its reply. Returning an error NAcks the
+// request without replying.
+type Handler[R any] func(ctx context.Context, request api.Message[R], reply api.Message[R]) error
+
+// Responder consumes requests from a queue, passes them to a Handler and enqueues the
+// replies to the queue named by each request's reply-to address.
+type Responder[R any] struct {
+	requests Queue[R]
+	replies  func(replyTo string) (Queue[R], error)
+	handler  Handler[R]
+
+	// onError is called with the requests that failed, and why.
+	onError func(ctx context.Context, request api.Message[R], err error)
+
+	// backoff and maxBackoff bound the wait after a failed request, which doubles
+	// with every consecutive failure.
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// ResponderOption configures a Responder.
+type ResponderOption[R any] func(*Responder[R])
+
+// OnRequestError sets a callback that is called with every request that could not be
+// handled or replied to, and the error, before the request is NAcked.
+func OnRequestError[R any](callback func(ctx context.Context, request api.Message[R], err error)) ResponderOption[R] {
+	return func(r *Responder[R]) {
+		r.onError = callback
+	}
+}
+
+// WithFailureBackoff sets how long Serve waits before dequeueing again after a request
+// failed, so that a request that keeps failing is not redelivered in a tight loop. The
+// wait starts at initial and doubles with every consecutive failure up to max. It
+// defaults to 100ms and 30s.
+func WithFailureBackoff[R any](initial time.Duration, max time.Duration) ResponderOption[R] {
+	return func(r *Responder[R]) {
+		r.backoff = initial
+		r.maxBackoff = max
+	}
+}
+
+// NewResponder returns a Responder that consumes requests from requests. replies
+// returns the queue to enqueue a reply to, given the reply-to name of the request.
+func NewResponder[R any](requests Queue[R], replies func(replyTo string) (Queue[R], error), handler Handler[R], opts ...ResponderOption[R]) *Responder[R] {
+
+	r := &Responder[R]{
+		requests:   requests,
+		replies:    replies,
+		handler:    handler,
+		backoff:    defaultFailureBackoff,
+		maxBackoff: defaultMaxFailureBackoff,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Serve handles requests until the context is cancelled, which it returns as its error,
+// or a request cannot be dequeued, acknowledged or NAcked. A request is acknowledged
+// once its reply has been enqueued, and NAcked if handling it or enqueueing the reply
+// fails, after which Serve backs off before the next request. Requests without a
+// reply-to address are handled and acknowledged without a reply.
+func (r *Responder[R]) Serve(ctx context.Context) error {
+
+	failures := 0
+	for {
+		req, err := r.requests.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		err = r.respond(ctx, req.Message())
+		if err == nil {
+			failures = 0
+			err = req.Ack(ctx)
+			if err != nil {
+				return fmt.Errorf("ezQue: failed to acknowledge request: %w", err)
+			}
+			continue
+		}
+
+		if r.onError != nil {
+			r.onError(ctx, req.Message(), err)
+		}
+		err = req.NAck(ctx)
+		if err != nil {
+			return fmt.Errorf("ezQue: failed to NAck request: %w", err)
+		}
+
+		failures++
+		err = r.wait(ctx, failures)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// wait backs off after the given number of consecutive failed requests, returning
+// the context's error if it is done first.
+func (r *Responder[R]) wait(ctx context.Context, failures int) error {
+
+	backoff := r.backoff
+	for i := 1; i < failures && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, r.maxBackoff)
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// respond handles a single request and enqueues its reply.
+func (r *Responder[R]) respond(ctx context.Context, req api.Message[R]) error {
+
+	correlated, ok := req.(api.Correlated)
+	if !ok || correlated.GetReplyTo() == "" {
+		return r.handler(ctx, req, r.requests.NewMessage())
+	}
+
+	replies, err := r.replies(correlated.GetReplyTo())
+	if err != nil {
+		return err
+	}
+
+	reply := replies.NewMessage()
+	err = r.handler(ctx, req, reply)
+	if err != nil {
+		return err
+	}
+
+	replyCorrelated, ok := reply.(api.Correlated)
+	if !ok {
+		return fmt.Errorf("ezQue: reply does not support correlation: %w", api.ErrNotSupported)
+	}
+	replyCorrelated.SetCorrelationID(correlated.GetCorrelationID())
+
+	return replies.Enqueue(ctx, reply)
+}
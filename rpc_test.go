@@ -0,0 +1,257 @@
+package ezQue
+
+import (
+	"context"
+	"errors"
+	"github.com/pgvanniekerk/ezQue/api"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rpcMessage is a fake api.Correlated message.
+type rpcMessage struct {
+	text          string
+	correlationID string
+	replyTo       string
+}
+
+func (m *rpcMessage) Raw() rpcMessage            { return *m }
+func (m *rpcMessage) Text() string               { return m.text }
+func (m *rpcMessage) SetRaw(raw rpcMessage)      { *m = raw }
+func (m *rpcMessage) SetText(text string)        { m.text = text }
+func (m *rpcMessage) GetCorrelationID() string   { return m.correlationID }
+func (m *rpcMessage) SetCorrelationID(id string) { m.correlationID = id }
+func (m *rpcMessage) GetReplyTo() string         { return m.replyTo }
+func (m *rpcMessage) SetReplyTo(replyTo string)  { m.replyTo = replyTo }
+
+// rpcDelivery is a dequeued rpcMessage. NAck puts it back at the end of its queue.
+type rpcDelivery struct {
+	msg   rpcMessage
+	queue *rpcQueue
+}
+
+func (d *rpcDelivery) Message() api.Message[rpcMessage] { return &d.msg }
+func (d *rpcDelivery) Ack(context.Context) error        { return nil }
+func (d *rpcDelivery) NAck(context.Context) error {
+	d.queue.push(d.msg)
+	return nil
+}
+
+// rpcQueue is an in-memory, concurrency safe enqueuer and dequeuer of rpcMessages.
+type rpcQueue struct {
+	mu   sync.Mutex
+	msgs []rpcMessage
+}
+
+func (q *rpcQueue) push(msg rpcMessage) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.msgs = append(q.msgs, msg)
+}
+
+// pop removes the first message accepted by match, polling until one is available.
+func (q *rpcQueue) pop(ctx context.Context, match func(rpcMessage) bool) (api.DequeueMessage[rpcMessage], error) {
+	for {
+		q.mu.Lock()
+		for i, msg := range q.msgs {
+			if match(msg) {
+				q.msgs = append(q.msgs[:i], q.msgs[i+1:]...)
+				q.mu.Unlock()
+				return &rpcDelivery{msg: msg, queue: q}, nil
+			}
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (q *rpcQueue) NewMessage() api.Message[rpcMessage] { return &rpcMessage{} }
+func (q *rpcQueue) Enqueue(_ context.Context, msg api.Message[rpcMessage]) error {
+	q.push(msg.Raw())
+	return nil
+}
+func (q *rpcQueue) Dequeue(ctx context.Context) (api.DequeueMessage[rpcMessage], error) {
+	return q.pop(ctx, func(rpcMessage) bool { return true })
+}
+func (q *rpcQueue) Disconnect(context.Context) error { return nil }
+
+// selectiveRPCQueue is an rpcQueue that supports api.SelectiveDequeuer.
+type selectiveRPCQueue struct {
+	rpcQueue
+}
+
+func (q *selectiveRPCQueue) DequeueWhere(ctx context.Context, sel api.Selector) (api.DequeueMessage[rpcMessage], error) {
+	return q.pop(ctx, func(msg rpcMessage) bool { return msg.correlationID == sel.CorrelationID })
+}
+
+// connectFake connects a Queue over the given fake enqueuer and dequeuer.
+func connectFake[R any](t *testing.T, fake interface {
+	api.Enqueuer[R]
+	api.Dequeuer[R]
+}) Queue[R] {
+	q, err := Connect(func(struct{}) (api.Enqueuer[R], api.Dequeuer[R], error) {
+		return fake, fake, nil
+	}, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func testRequestReply(t *testing.T, replyQueue Queue[rpcMessage]) {
+	requests := connectFake[rpcMessage](t, &rpcQueue{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// An unrelated reply sits in front of ours
+	_ = replyQueue.Enqueue(ctx, &rpcMessage{text: "someone else's reply", correlationID: "other"})
+
+	responder := NewResponder(requests, func(replyTo string) (Queue[rpcMessage], error) {
+		if replyTo != "replies" {
+			return nil, errors.New("unknown reply queue")
+		}
+		return replyQueue, nil
+	}, func(_ context.Context, req api.Message[rpcMessage], reply api.Message[rpcMessage]) error {
+		reply.SetText("re: " + req.Text())
+		return nil
+	})
+	go func() { _ = responder.Serve(ctx) }()
+
+	requester := NewRequester(requests, replyQueue, "replies")
+	for _, text := range []string{"first", "second"} {
+		reply, err := requester.Request(ctx, &rpcMessage{text: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reply.Message().Text() != "re: "+text {
+			t.Fatalf("expected reply to %q, got %q", text, reply.Message().Text())
+		}
+		if err := reply.Ack(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestRequestReply_Selective ensures that replies are dequeued by correlation ID.
+func TestRequestReply_Selective(t *testing.T) {
+	testRequestReply(t, connectFake[rpcMessage](t, &selectiveRPCQueue{}))
+}
+
+// TestRequest_NotSelective ensures that requests are refused, before they are enqueued,
+// when replies cannot be dequeued by their correlation ID.
+func TestRequest_NotSelective(t *testing.T) {
+	requests := &rpcQueue{}
+	replies := connectFake[rpcMessage](t, &rpcQueue{})
+
+	_, err := NewRequester(connectFake[rpcMessage](t, requests), replies, "replies").Request(context.Background(), &rpcMessage{text: "a"})
+	if !errors.Is(err, api.ErrNotSupported) {
+		t.Fatalf("expected api.ErrNotSupported, got %v", err)
+	}
+	if len(requests.msgs) != 0 {
+		t.Fatalf("expected nothing to be enqueued, got %v", requests.msgs)
+	}
+}
+
+// selectiveFakeQueue is a fakeQueue that supports api.SelectiveDequeuer.
+type selectiveFakeQueue struct {
+	fakeQueue
+}
+
+func (q *selectiveFakeQueue) DequeueWhere(ctx context.Context, _ api.Selector) (api.DequeueMessage[string], error) {
+	return q.Dequeue(ctx)
+}
+
+// TestRequest_NotCorrelated ensures that messages without correlation support are rejected.
+func TestRequest_NotCorrelated(t *testing.T) {
+	fake := &selectiveFakeQueue{}
+	q := connectFake[string](t, fake)
+
+	_, err := NewRequester(q, q, "replies").Request(context.Background(), &fakeMessage{text: "a"})
+	if !errors.Is(err, api.ErrNotSupported) {
+		t.Fatalf("expected api.ErrNotSupported, got %v", err)
+	}
+	if len(fake.msgs) != 0 {
+		t.Fatalf("expected nothing to be enqueued, got %v", fake.msgs)
+	}
+}
+
+// TestServe_Cancelled ensures that Serve returns once its context is cancelled.
+func TestServe_Cancelled(t *testing.T) {
+	q := connectFake[rpcMessage](t, &rpcQueue{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := NewResponder(q, nil, nil).Serve(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestServe_HandlerError ensures that failed requests are reported and NAcked, and
+// that Serve backs off instead of spinning on a request that keeps failing.
+func TestServe_HandlerError(t *testing.T) {
+	q := connectFake[rpcMessage](t, &rpcQueue{msgs: []rpcMessage{{text: "poison"}}})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	handled, reported := 0, 0
+	responder := NewResponder(q, nil, func(context.Context, api.Message[rpcMessage], api.Message[rpcMessage]) error {
+		handled++
+		return errors.New("handler failed")
+	}, OnRequestError(func(_ context.Context, req api.Message[rpcMessage], err error) {
+		if req.Text() != "poison" || err == nil {
+			t.Errorf("unexpected request error %q: %v", req.Text(), err)
+		}
+		reported++
+	}), WithFailureBackoff[rpcMessage](20*time.Millisecond, 40*time.Millisecond))
+
+	err := responder.Serve(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if handled == 0 || handled > 5 {
+		t.Fatalf("expected the request to be retried with backoff, handled %d times", handled)
+	}
+	if reported != handled {
+		t.Fatalf("expected %d reported errors, got %d", handled, reported)
+	}
+}
+
+// nackFailingQueue is a fakeQueue whose messages cannot be NAcked.
+type nackFailingQueue struct {
+	fakeQueue
+}
+
+type nackFailingMessage struct {
+	fakeMessage
+}
+
+func (m *nackFailingMessage) Message() api.Message[string] { return &m.fakeMessage }
+func (m *nackFailingMessage) NAck(context.Context) error   { return api.ErrConnectionLost }
+
+func (q *nackFailingQueue) Dequeue(ctx context.Context) (api.DequeueMessage[string], error) {
+	msg, err := q.fakeQueue.Dequeue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &nackFailingMessage{fakeMessage: fakeMessage{text: msg.Message().Text()}}, nil
+}
+
+// TestServe_NAckError ensures that Serve returns when a failed request cannot be NAcked.
+func TestServe_NAckError(t *testing.T) {
+	q := connectFake[string](t, &nackFailingQueue{fakeQueue: fakeQueue{msgs: []string{"a"}}})
+
+	err := NewResponder(q, nil, func(context.Context, api.Message[string], api.Message[string]) error {
+		return errors.New("handler failed")
+	}).Serve(context.Background())
+	if !errors.Is(err, api.ErrConnectionLost) {
+		t.Fatalf("expected api.ErrConnectionLost, got %v", err)
+	}
+}
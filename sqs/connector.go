@@ -0,0 +1,268 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/sqs"
+	"time"
+)
+
+const (
+	// defaultWaitTime is used when no WithWaitTime option is given. It is
+	// the longest long poll SQS allows.
+	defaultWaitTime = 20 * time.Second
+
+	// minWaitTime is the shortest wait time accepted. ReceiveMessage takes
+	// whole seconds, and a wait time of zero would make Dequeue busy-poll.
+	minWaitTime = time.Second
+)
+
+// AmazonSQS is provided as a queueConnector to ezQueue.Connect method, to connect to an Amazon SQS queue.
+func AmazonSQS(options OptionFunc) (api.Enqueuer[sqs.Message], api.Dequeuer[sqs.Message], error) {
+	return connect(options)
+}
+
+// connect establishes both enqueue and dequeue connections.
+func connect(opts OptionFunc) (api.Enqueuer[sqs.Message], api.Dequeuer[sqs.Message], error) {
+
+	// Initialise Enqueuer
+	enq, err := connectEnqueue(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialise Dequeuer
+	deq, err := connectDequeue(opts)
+	if err != nil {
+		_ = enq.Disconnect(context.Background())
+		return nil, nil, err
+	}
+
+	return enq, deq, nil
+}
+
+// connectEnqueue establishes an enqueue connection.
+func connectEnqueue(options OptionFunc) (api.Enqueuer[sqs.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("sqs: options is nil")
+	}
+	opts := options()
+
+	// Validate queue name
+	if opts.queueName == "" {
+		return nil, fmt.Errorf("sqs: queue name is empty")
+	}
+
+	connOpts, err := buildConnOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	awsConfig, err := connOpts.resolveAWSConfig(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// resolve the queue URL
+	client := awssqs.NewFromConfig(awsConfig, connOpts.clientOpts...)
+	queueURL, err := resolveQueueURL(client, opts.queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	enq := sqs.NewEnqueuer(client, queueURL, connOpts.groupID)
+	return enq, nil
+}
+
+// connectDequeue establishes a dequeue connection.
+func connectDequeue(options OptionFunc) (api.Dequeuer[sqs.Message], error) {
+
+	// Get the Options
+	if options == nil {
+		return nil, fmt.Errorf("sqs: options is nil")
+	}
+	opts := options()
+
+	// Validate queue name
+	if opts.queueName == "" {
+		return nil, fmt.Errorf("sqs: queue name is empty")
+	}
+
+	connOpts, err := buildConnOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	awsConfig, err := connOpts.resolveAWSConfig(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// resolve the queue URL
+	client := awssqs.NewFromConfig(awsConfig, connOpts.clientOpts...)
+	queueURL, err := resolveQueueURL(client, opts.queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	deq := sqs.NewDequeuer(client, queueURL, int32(connOpts.waitTime/time.Second), int32(connOpts.visibilityTimeout/time.Second))
+	return deq, nil
+}
+
+// resolveQueueURL looks up the URL of the named queue, which also verifies
+// that the queue exists and that the credentials are valid.
+func resolveQueueURL(client *awssqs.Client, queueName string) (string, error) {
+	out, err := client.GetQueueUrl(context.Background(), &awssqs.GetQueueUrlInput{
+		QueueName: aws.String(queueName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("sqs: failed to resolve queue url: %w", err)
+	}
+	return aws.ToString(out.QueueUrl), nil
+}
+
+// buildConnOptions applies the ConnOptionFuncs over the defaults and validates
+// the result.
+func buildConnOptions(opts Options) (*connOptions, error) {
+	connOpts := &connOptions{
+		waitTime: defaultWaitTime,
+	}
+	for _, opt := range opts.connOpts {
+		opt(connOpts)
+	}
+	if connOpts.waitTime < minWaitTime || connOpts.waitTime > defaultWaitTime {
+		return nil, fmt.Errorf("sqs: wait time %s is not between %s and %s", connOpts.waitTime, minWaitTime, defaultWaitTime)
+	}
+	return connOpts, nil
+}
+
+// resolveAWSConfig returns the AWS configuration given with WithAWSConfig, or
+// otherwise loads one from the default chain (environment variables, shared
+// config and credentials files, SSO, web identity and instance roles). The
+// region and credentials set by InRegion and AuthenticatedWith are applied on
+// top of it.
+func (opts *connOptions) resolveAWSConfig(ctx context.Context) (aws.Config, error) {
+	var awsConfig aws.Config
+	if opts.awsConfig != nil {
+		awsConfig = opts.awsConfig.Copy()
+	} else {
+		var err error
+		awsConfig, err = config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return aws.Config{}, fmt.Errorf("sqs: failed to load aws config: %w", err)
+		}
+	}
+	if opts.region != "" {
+		awsConfig.Region = opts.region
+	}
+	if opts.credentials != nil {
+		awsConfig.Credentials = opts.credentials
+	}
+	return awsConfig, nil
+}
+
+// Options struct holds connection options and the queue name.
+type Options struct {
+	connOpts  []ConnOptionFunc
+	queueName string
+}
+
+// OptionFunc is a function type that returns Options.
+type OptionFunc func() Options
+
+// Queue returns an OptionFunc that holds connection options and a queue name.
+// Names ending in ".fifo" refer to FIFO queues.
+func Queue(queueName string, connOpts ...ConnOptionFunc) OptionFunc {
+	return func() Options {
+		return Options{
+			connOpts:  connOpts,
+			queueName: queueName,
+		}
+	}
+}
+
+// ConnOptionFunc is a function type to set connOptions.
+type ConnOptionFunc func(*connOptions)
+
+// connOptions struct holds the information required for creating connections.
+type connOptions struct {
+	awsConfig         *aws.Config
+	region            string
+	credentials       aws.CredentialsProvider
+	clientOpts        []func(*awssqs.Options)
+	groupID           string
+	waitTime          time.Duration
+	visibilityTimeout time.Duration
+}
+
+// InRegion sets the AWS region for ConnOptionFunc.
+func InRegion(region string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.region = region
+	}
+}
+
+// AuthenticatedWith sets a static access key id and secret access key for ConnOptionFunc.
+// Without it, credentials are resolved through the AWS default chain.
+func AuthenticatedWith(accessKeyID string, secretAccessKey string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
+	}
+}
+
+// UsingEndpoint sets the base URL of the SQS service, for SQS-compatible servers
+// such as ElasticMQ or LocalStack.
+func UsingEndpoint(endpoint string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.clientOpts = append(opts.clientOpts, func(o *awssqs.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		})
+	}
+}
+
+// WithAWSConfig uses cfg instead of loading the AWS configuration from the
+// default chain. InRegion and AuthenticatedWith still take precedence over
+// the region and credentials of cfg, whichever order they are given in.
+func WithAWSConfig(cfg aws.Config) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.awsConfig = &cfg
+	}
+}
+
+// WithSQSOptions allows any other SQS client option to be set.
+func WithSQSOptions(fn func(*awssqs.Options)) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.clientOpts = append(opts.clientOpts, fn)
+	}
+}
+
+// InMessageGroup sets the message group used for messages that do not set their
+// own GroupID. It is required for FIFO queues unless every message sets GroupID.
+func InMessageGroup(groupID string) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.groupID = groupID
+	}
+}
+
+// WithWaitTime sets how long a single ReceiveMessage long polls for messages. It is
+// rounded down to whole seconds and must be between 1 and 20 seconds; Connect
+// fails otherwise.
+func WithWaitTime(waitTime time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.waitTime = waitTime
+	}
+}
+
+// WithVisibilityTimeout sets how long a dequeued message stays hidden from other
+// consumers before it is delivered again if it is not acknowledged. It is rounded
+// down to whole seconds. It defaults to the visibility timeout of the queue.
+func WithVisibilityTimeout(visibilityTimeout time.Duration) ConnOptionFunc {
+	return func(opts *connOptions) {
+		opts.visibilityTimeout = visibilityTimeout
+	}
+}
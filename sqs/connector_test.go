@@ -0,0 +1,182 @@
+package sqs
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/pgvanniekerk/ezQue/internal/sqs"
+	"github.com/pgvanniekerk/ezQue/internal/sqs/sqstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestConnector ensures that AmazonSQS can be provided
+// to the ezQue.Connect function.
+func TestConnector(t *testing.T) {
+	_, _ = ezQue.Connect(AmazonSQS, nil)
+}
+
+// TestConnOptions ensures that the ConnOptionFuncs correctly set the config values
+func TestConnOptions(t *testing.T) {
+	opts, err := buildConnOptions(Queue("queue",
+		InRegion("af-south-1"),
+		AuthenticatedWith("key", "secret"),
+		WithAWSConfig(aws.Config{Region: "eu-west-1"}),
+		UsingEndpoint("http://localhost:9324"),
+		WithSQSOptions(func(o *awssqs.Options) { o.RetryMaxAttempts = 1 }),
+		InMessageGroup("group"),
+		WithWaitTime(5*time.Second),
+		WithVisibilityTimeout(time.Minute),
+	)())
+	require.NoError(t, err)
+
+	awsConfig, err := opts.resolveAWSConfig(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "af-south-1", awsConfig.Region)
+	creds, err := awsConfig.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "key", creds.AccessKeyID)
+	require.Equal(t, "secret", creds.SecretAccessKey)
+	require.Equal(t, "group", opts.groupID)
+	require.Equal(t, 5*time.Second, opts.waitTime)
+	require.Equal(t, time.Minute, opts.visibilityTimeout)
+
+	clientOpts := awssqs.Options{}
+	for _, fn := range opts.clientOpts {
+		fn(&clientOpts)
+	}
+	require.Equal(t, "http://localhost:9324", aws.ToString(clientOpts.BaseEndpoint))
+	require.Equal(t, 1, clientOpts.RetryMaxAttempts)
+}
+
+// TestConnOptions_DefaultChain ensures that the region and credentials are
+// resolved through the AWS default chain when they are not given explicitly.
+func TestConnOptions_DefaultChain(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_REGION", "eu-central-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+	opts, err := buildConnOptions(Queue("queue")())
+	require.NoError(t, err)
+	require.Equal(t, defaultWaitTime, opts.waitTime)
+
+	awsConfig, err := opts.resolveAWSConfig(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "eu-central-1", awsConfig.Region)
+	creds, err := awsConfig.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "env-key", creds.AccessKeyID)
+	require.NotEqual(t, "ezQue", creds.Source)
+}
+
+// TestConnOptions_WaitTime ensures that wait times which ReceiveMessage cannot
+// honour are rejected rather than rounded down to a busy poll.
+func TestConnOptions_WaitTime(t *testing.T) {
+	for _, waitTime := range []time.Duration{0, 500 * time.Millisecond, 21 * time.Second} {
+		_, err := buildConnOptions(Queue("queue", WithWaitTime(waitTime))())
+		require.Error(t, err, waitTime)
+	}
+	_, err := buildConnOptions(Queue("queue", WithWaitTime(time.Second))())
+	require.NoError(t, err)
+}
+
+//
+// connect
+//
+
+type ConnectTestSuite struct {
+	suite.Suite
+	srv *sqstest.Server
+}
+
+func TestConnectTestSuite(t *testing.T) {
+	suite.Run(t, new(ConnectTestSuite))
+}
+
+func (suite *ConnectTestSuite) SetupTest() {
+	suite.srv = sqstest.NewServer()
+}
+
+func (suite *ConnectTestSuite) TearDownTest() {
+	suite.srv.Close()
+}
+
+// queue returns an OptionFunc for the named queue on the stand-in server.
+func (suite *ConnectTestSuite) queue(name string, connOpts ...ConnOptionFunc) OptionFunc {
+	return Queue(name, append([]ConnOptionFunc{
+		InRegion("us-east-1"),
+		AuthenticatedWith("key", "secret"),
+		UsingEndpoint(suite.srv.URL),
+		WithWaitTime(time.Second),
+	}, connOpts...)...)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnNilOptions() {
+	_, _, err := connect(nil)
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnEmptyQueueName() {
+	_, _, err := connect(suite.queue(""))
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *ConnectTestSuite) Test_FailOnMissingQueue() {
+	_, _, err := connect(suite.queue("pfft"))
+	assert.NotNil(suite.T(), err)
+}
+
+// Test_EnqueueDequeue tests a round trip through a Queue connected with AmazonSQS.
+func (suite *ConnectTestSuite) Test_EnqueueDequeue() {
+	suite.srv.CreateQueue("orders")
+	q, err := ezQue.Connect(AmazonSQS, suite.queue("orders"))
+	suite.Require().NoError(err)
+	defer func() { suite.NoError(q.Disconnect(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg := q.NewMessage()
+	msg.SetText("test message")
+	suite.Require().NoError(q.Enqueue(ctx, msg))
+
+	deqMsg, err := q.Dequeue(ctx)
+	suite.Require().NoError(err)
+	suite.Equal("test message", deqMsg.Message().Text())
+	suite.NoError(deqMsg.Ack(ctx))
+	suite.Zero(suite.srv.Len("orders"))
+}
+
+// Test_Batch tests that the batch helpers use the batch APIs of a FIFO queue.
+func (suite *ConnectTestSuite) Test_Batch() {
+	suite.srv.CreateQueue("orders.fifo")
+	q, err := ezQue.Connect(AmazonSQS, suite.queue("orders.fifo", InMessageGroup("group")))
+	suite.Require().NoError(err)
+	defer func() { suite.NoError(q.Disconnect(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msgs := []api.Message[sqs.Message]{
+		&sqs.Message{Content: "first", DeduplicationID: "1"},
+		&sqs.Message{Content: "second", DeduplicationID: "2"},
+	}
+	suite.Require().NoError(ezQue.EnqueueBatch(ctx, q, msgs))
+
+	batch, err := ezQue.DequeueBatch(ctx, q, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(batch, 2)
+	suite.Equal("first", batch[0].Message().Text())
+	suite.Equal("second", batch[1].Message().Text())
+	for _, deqMsg := range batch {
+		suite.NoError(deqMsg.Ack(ctx))
+	}
+}
@@ -0,0 +1,21 @@
+// Package sqs provides Go functions and types for working with Amazon SQS, and SQS-compatible
+// servers, through the ezQue Queue abstraction.
+//
+// Central to the package is the AmazonSQS function. This function takes in an OptionFunc parameter
+// for customizing the connection options and returns Enqueuer and Dequeuer instances associated
+// with the specified queue.
+//
+// Enqueue sends a message with SendMessage. Dequeue long polls the queue with ReceiveMessage,
+// which hides the received message from other consumers for the visibility timeout. Ack deletes
+// the message with DeleteMessage, and NAck makes it visible again straight away with
+// ChangeMessageVisibility. A message that is neither acknowledged nor negatively acknowledged is
+// delivered again once its visibility timeout expires.
+//
+// Both the Enqueuer and the Dequeuer support batching through api.BatchEnqueuer and
+// api.BatchDequeuer, which map to SendMessageBatch and ReceiveMessage for up to ten messages.
+//
+// FIFO queues, whose names end in ".fifo", need a message group and a deduplication ID for every
+// message. The group defaults to the one given with InMessageGroup.
+//
+// Note: This package relies on "ezQue/api", "ezQue/internal/sqs" and "github.com/aws/aws-sdk-go-v2".
+package sqs
@@ -0,0 +1,30 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+)
+
+// Stats returns a snapshot of the messages in q. It returns api.ErrNotSupported if q
+// does not support api.Inspector.
+func Stats[R any](ctx context.Context, q Queue[R]) (api.Stats, error) {
+
+	inspector, ok := q.(api.Inspector)
+	if !ok {
+		return api.Stats{}, api.ErrNotSupported
+	}
+
+	return inspector.Stats(ctx)
+}
+
+// Stats delegates to the dequeuer if it supports api.Inspector, and otherwise
+// returns api.ErrNotSupported.
+func (q *queue[R]) Stats(ctx context.Context) (api.Stats, error) {
+
+	inspector, ok := q.dequeuer.(api.Inspector)
+	if !ok {
+		return api.Stats{}, api.ErrNotSupported
+	}
+
+	return inspector.Stats(ctx)
+}
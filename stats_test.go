@@ -0,0 +1,39 @@
+package ezQue
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue/api"
+	"testing"
+)
+
+// fakeInspector is a fakeQueue that supports api.Inspector.
+type fakeInspector struct {
+	fakeQueue
+}
+
+func (q *fakeInspector) Stats(context.Context) (api.Stats, error) {
+	return api.Stats{Ready: int64(len(q.msgs))}, nil
+}
+
+// TestStats ensures that Stats delegates to an api.Inspector.
+func TestStats(t *testing.T) {
+	q := connectFake[string](t, &fakeInspector{fakeQueue{msgs: []string{"a", "b"}}})
+
+	stats, err := Stats(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Ready != 2 {
+		t.Fatalf("expected 2 ready messages, got %d", stats.Ready)
+	}
+}
+
+// TestStatsNotSupported ensures that Stats reports queue systems without an api.Inspector.
+func TestStatsNotSupported(t *testing.T) {
+	q := connectFake[string](t, &fakeQueue{})
+
+	_, err := Stats(context.Background(), q)
+	if err != api.ErrNotSupported {
+		t.Fatalf("expected api.ErrNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+// Package tracing adds OpenTelemetry tracing to an ezQue Queue, whichever queue system it connects to.
+//
+// Central to the package is the Interceptors function, whose result is passed to ezQue.Connect
+// with ezQue.WithInterceptors. Every Enqueue is traced with a producer span, and the W3C
+// traceparent of that span is injected into the properties of the message. Every Dequeue starts
+// a consumer span, linked to the producer span extracted from the message, that ends when the
+// message is acknowledged with Ack or NAck. Context returns the context of the consumer span, so
+// that the work done for the message can be traced within it.
+//
+// Trace context is only propagated for messages that implement api.Properties, which the
+// messages of every bundled queue system do. Other messages are traced without it.
+//
+// The package uses only the OpenTelemetry API. The TracerProvider and the propagator default to
+// the global ones, and can be set with WithTracerProvider and WithPropagator.
+//
+// Note: This package relies on "ezQue", "ezQue/api" and "go.opentelemetry.io/otel".
+package tracing
@@ -0,0 +1,233 @@
+package tracing
+
+import (
+	"context"
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/pgvanniekerk/ezQue/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the tracer of this package.
+const instrumentationName = "github.com/pgvanniekerk/ezQue/tracing"
+
+// Options holds the tracer provider, propagator and messaging attributes used to
+// trace a Queue.
+type Options struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	system         string
+	destination    string
+}
+
+// OptionFunc is a function type to set Options.
+type OptionFunc func(*Options)
+
+// WithTracerProvider sets the TracerProvider spans are created with. It defaults to
+// the global TracerProvider.
+func WithTracerProvider(provider trace.TracerProvider) OptionFunc {
+	return func(opts *Options) {
+		opts.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator trace context is injected into and extracted
+// from messages with. It defaults to the global propagator.
+func WithPropagator(propagator propagation.TextMapPropagator) OptionFunc {
+	return func(opts *Options) {
+		opts.propagator = propagator
+	}
+}
+
+// WithSystem sets the messaging.system attribute of the spans, such as "oracle_aq".
+func WithSystem(system string) OptionFunc {
+	return func(opts *Options) {
+		opts.system = system
+	}
+}
+
+// WithDestination sets the name of the queue, which the spans are named after and
+// record as the messaging.destination.name attribute.
+func WithDestination(destination string) OptionFunc {
+	return func(opts *Options) {
+		opts.destination = destination
+	}
+}
+
+// Interceptors returns the interceptors that trace a Queue, to be passed to
+// ezQue.Connect with ezQue.WithInterceptors.
+func Interceptors[R any](optFuncs ...OptionFunc) ezQue.Interceptors[R] {
+
+	opts := Options{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, optFunc := range optFuncs {
+		optFunc(&opts)
+	}
+
+	t := &tracer[R]{
+		tracer:     opts.tracerProvider.Tracer(instrumentationName),
+		propagator: opts.propagator,
+		opts:       opts,
+	}
+
+	return ezQue.Interceptors[R]{
+		Enqueue: t.enqueue,
+		Dequeue: t.dequeue,
+	}
+}
+
+// Context returns ctx with the consumer span of msg, which was dequeued from a traced
+// Queue, so that the work done for the message can be traced within it. If msg has no
+// consumer span, ctx is returned as it is.
+func Context[R any](ctx context.Context, msg api.DequeueMessage[R]) context.Context {
+
+	for msg != nil {
+		switch m := msg.(type) {
+		case *tracedMessage[R]:
+			return trace.ContextWithSpan(ctx, m.span)
+		case interface{ Unwrap() api.DequeueMessage[R] }:
+			msg = m.Unwrap()
+		default:
+			return ctx
+		}
+	}
+
+	return ctx
+}
+
+// tracer creates the spans of a traced Queue.
+type tracer[R any] struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	opts       Options
+}
+
+// enqueue traces an enqueue with a producer span, injecting its trace context into
+// the message properties.
+func (t *tracer[R]) enqueue(ctx context.Context, msg api.Message[R], next ezQue.EnqueueFunc[R]) error {
+
+	ctx, span := t.tracer.Start(ctx, t.spanName("publish"),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(t.attributes("publish")...),
+	)
+	defer span.End()
+
+	if properties, ok := msg.(api.Properties); ok {
+		t.propagator.Inject(ctx, carrier{properties})
+	}
+
+	err := next(ctx, msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// dequeue starts a consumer span for the dequeued message, linked to the producer
+// span in its properties, that ends when the message is acknowledged.
+func (t *tracer[R]) dequeue(ctx context.Context, next ezQue.DequeueFunc[R]) (api.DequeueMessage[R], error) {
+
+	msg, err := next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(t.attributes("process")...),
+	}
+	if properties, ok := msg.Message().(api.Properties); ok {
+		producer := trace.SpanContextFromContext(t.propagator.Extract(context.Background(), carrier{properties}))
+		if producer.IsValid() {
+			startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: producer}))
+		}
+	}
+
+	_, span := t.tracer.Start(ctx, t.spanName("process"), startOpts...)
+
+	return &tracedMessage[R]{
+		DequeueMessage: msg,
+		span:           span,
+	}, nil
+}
+
+// spanName names a span after the operation and, if known, the queue.
+func (t *tracer[R]) spanName(operation string) string {
+	if t.opts.destination == "" {
+		return operation
+	}
+	return operation + " " + t.opts.destination
+}
+
+// attributes returns the messaging attributes of a span for the operation.
+func (t *tracer[R]) attributes(operation string) []attribute.KeyValue {
+
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.operation.type", operation),
+	}
+	if t.opts.system != "" {
+		attrs = append(attrs, attribute.String("messaging.system", t.opts.system))
+	}
+	if t.opts.destination != "" {
+		attrs = append(attrs, attribute.String("messaging.destination.name", t.opts.destination))
+	}
+
+	return attrs
+}
+
+// tracedMessage is a dequeued message whose consumer span ends when it is
+// acknowledged.
+type tracedMessage[R any] struct {
+	api.DequeueMessage[R]
+	span trace.Span
+}
+
+// Unwrap returns the dequeued message.
+func (m *tracedMessage[R]) Unwrap() api.DequeueMessage[R] {
+	return m.DequeueMessage
+}
+
+func (m *tracedMessage[R]) Ack(ctx context.Context) error {
+	return m.end(m.DequeueMessage.Ack(ctx), "ack")
+}
+
+func (m *tracedMessage[R]) NAck(ctx context.Context) error {
+	return m.end(m.DequeueMessage.NAck(ctx), "nack")
+}
+
+// end records how the message was acknowledged, and any error, and ends the span.
+func (m *tracedMessage[R]) end(err error, settlement string) error {
+
+	m.span.SetAttributes(attribute.String("messaging.settlement", settlement))
+	if err != nil {
+		m.span.RecordError(err)
+		m.span.SetStatus(codes.Error, err.Error())
+	}
+	m.span.End()
+
+	return err
+}
+
+// carrier adapts api.Properties to a propagation.TextMapCarrier.
+type carrier struct {
+	properties api.Properties
+}
+
+func (c carrier) Get(key string) string {
+	return c.properties.GetProperty(key)
+}
+
+func (c carrier) Set(key string, value string) {
+	c.properties.SetProperty(key, value)
+}
+
+func (c carrier) Keys() []string {
+	return c.properties.PropertyKeys()
+}
@@ -0,0 +1,147 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pgvanniekerk/ezQue"
+	"github.com/pgvanniekerk/ezQue/api"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// message is a fake api.Message with api.Properties.
+type message struct {
+	text       string
+	properties map[string]string
+}
+
+func (m *message) Raw() message        { return *m }
+func (m *message) Text() string        { return m.text }
+func (m *message) SetRaw(raw message)  { *m = raw }
+func (m *message) SetText(text string) { m.text = text }
+func (m *message) GetProperty(key string) string {
+	return m.properties[key]
+}
+func (m *message) SetProperty(key string, value string) {
+	if m.properties == nil {
+		m.properties = make(map[string]string)
+	}
+	m.properties[key] = value
+}
+func (m *message) PropertyKeys() []string {
+	keys := make([]string, 0, len(m.properties))
+	for key := range m.properties {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// delivery is a dequeued message that records how it was acknowledged.
+type delivery struct {
+	msg     message
+	settled string
+}
+
+func (d *delivery) Message() api.Message[message] { return &d.msg }
+func (d *delivery) Ack(context.Context) error {
+	d.settled = "ack"
+	return nil
+}
+func (d *delivery) NAck(context.Context) error {
+	d.settled = "nack"
+	return errors.New("nack failed")
+}
+
+// fakeQueue is an in-memory enqueuer and dequeuer of messages.
+type fakeQueue struct {
+	msgs []message
+}
+
+func (q *fakeQueue) NewMessage() api.Message[message] { return &message{} }
+func (q *fakeQueue) Enqueue(_ context.Context, msg api.Message[message]) error {
+	q.msgs = append(q.msgs, msg.Raw())
+	return nil
+}
+func (q *fakeQueue) Dequeue(context.Context) (api.DequeueMessage[message], error) {
+	if len(q.msgs) == 0 {
+		return nil, context.DeadlineExceeded
+	}
+	msg := q.msgs[0]
+	q.msgs = q.msgs[1:]
+	return &delivery{msg: msg}, nil
+}
+func (q *fakeQueue) Disconnect(context.Context) error { return nil }
+
+// connectTraced connects a traced Queue over a fakeQueue, recording spans in memory.
+func connectTraced(t *testing.T) (ezQue.Queue[message], *tracetest.InMemoryExporter, trace.Tracer) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	fake := &fakeQueue{}
+	q, err := ezQue.Connect(func(struct{}) (api.Enqueuer[message], api.Dequeuer[message], error) {
+		return fake, fake, nil
+	}, struct{}{}, ezQue.WithInterceptors(Interceptors[message](
+		WithTracerProvider(provider),
+		WithPropagator(propagation.TraceContext{}),
+		WithSystem("fake"),
+		WithDestination("orders"),
+	)))
+	require.NoError(t, err)
+
+	return q, exporter, provider.Tracer("test")
+}
+
+func TestTracing(t *testing.T) {
+	q, exporter, tracer := connectTraced(t)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	msg := &message{text: "hello"}
+	require.NoError(t, q.Enqueue(ctx, msg))
+	parent.End()
+	require.NotEmpty(t, msg.properties["traceparent"], "traceparent was not injected")
+
+	deqMsg, err := q.Dequeue(context.Background())
+	require.NoError(t, err)
+
+	// Work done for the message is traced within the consumer span
+	_, child := tracer.Start(Context(context.Background(), deqMsg), "handle")
+	child.End()
+	require.NoError(t, deqMsg.Ack(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	producer, consumer, handle := spans[0], spans[3], spans[2]
+
+	require.Equal(t, "publish orders", producer.Name)
+	require.Equal(t, trace.SpanKindProducer, producer.SpanKind)
+	require.Equal(t, parent.SpanContext().SpanID(), producer.Parent.SpanID())
+
+	require.Equal(t, "process orders", consumer.Name)
+	require.Equal(t, trace.SpanKindConsumer, consumer.SpanKind)
+	require.Len(t, consumer.Links, 1)
+	require.Equal(t, producer.SpanContext.SpanID(), consumer.Links[0].SpanContext.SpanID())
+	require.Equal(t, producer.SpanContext.TraceID(), consumer.Links[0].SpanContext.TraceID())
+
+	require.Equal(t, consumer.SpanContext.SpanID(), handle.Parent.SpanID())
+}
+
+func TestTracing_NAckError(t *testing.T) {
+	q, exporter, _ := connectTraced(t)
+
+	require.NoError(t, q.Enqueue(context.Background(), &message{text: "hello"}))
+	deqMsg, err := q.Dequeue(context.Background())
+	require.NoError(t, err)
+
+	// The consumer span stays open until the message is acknowledged
+	require.Len(t, exporter.GetSpans(), 1)
+	require.Error(t, deqMsg.NAck(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "Error", spans[1].Status.Code.String())
+}